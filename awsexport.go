@@ -0,0 +1,241 @@
+package edy
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	awsExportSummaryFile  = "manifest-summary.json"
+	awsExportManifestFile = "manifest-files.json"
+	awsExportDynamoDBJSON = "DYNAMODB_JSON"
+	awsExportIon          = "ION"
+)
+
+// awsExportSummary is manifest-summary.json of the table export to S3.
+type awsExportSummary struct {
+	OutputFormat       string `json:"outputFormat"`
+	ExportType         string `json:"exportType"`
+	ManifestFilesS3Key string `json:"manifestFilesS3Key"`
+}
+
+// awsExportDataFile is the line of manifest-files.json.
+type awsExportDataFile struct {
+	DataFileS3Key string `json:"dataFileS3Key"`
+}
+
+// awsExportItemReader reads the items of the table export to S3 which is downloaded to dir.
+// The data files are in dir/data, and they are read one by one.
+type awsExportItemReader struct {
+	f      func(string) (io.ReadCloser, error)
+	format string
+	files  []string
+	// file and decode are the data file which is being read.
+	file   io.ReadCloser
+	name   string
+	decode func() (map[string]types.AttributeValue, error)
+}
+
+func readAWSExportManifest(dir string, f func(string) (io.ReadCloser, error)) (*awsExportSummary, []string, error) {
+	in, err := f(filepath.Join(dir, awsExportSummaryFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer in.Close()
+	var s awsExportSummary
+	if err := json.NewDecoder(in).Decode(&s); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %v", awsExportSummaryFile, err)
+	}
+	switch s.OutputFormat {
+	case awsExportDynamoDBJSON, awsExportIon:
+	default:
+		return nil, nil, fmt.Errorf("unsupported export format: %s", s.OutputFormat)
+	}
+	if s.ExportType == "INCREMENTAL_EXPORT" {
+		return nil, nil, fmt.Errorf("incremental export is not supported")
+	}
+
+	manifest := awsExportManifestFile
+	if len(s.ManifestFilesS3Key) != 0 {
+		manifest = path.Base(s.ManifestFilesS3Key)
+	}
+	mf, err := f(filepath.Join(dir, manifest))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer mf.Close()
+	var files []string
+	d := json.NewDecoder(mf)
+	for {
+		var df awsExportDataFile
+		if err := d.Decode(&df); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %v", manifest, err)
+		}
+		files = append(files, filepath.Join(dir, "data", path.Base(df.DataFileS3Key)))
+	}
+	return &s, files, nil
+}
+
+func newAWSExportItemReader(dir string, f func(string) (io.ReadCloser, error)) (*awsExportItemReader, error) {
+	s, files, err := readAWSExportManifest(dir, f)
+	if err != nil {
+		return nil, err
+	}
+	return &awsExportItemReader{
+		f:      f,
+		format: s.OutputFormat,
+		files:  files,
+	}, nil
+}
+
+func (r *awsExportItemReader) open(name string) error {
+	file, err := r.f(name)
+	if err != nil {
+		return err
+	}
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	r.file, r.name = file, name
+
+	if r.format == awsExportIon {
+		d := newIonDecoder(gr)
+		r.decode = func() (map[string]types.AttributeValue, error) {
+			v, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			m, ok := v.(*types.AttributeValueMemberM)
+			if !ok {
+				return nil, fmt.Errorf("invalid export data, the line must be {Item:{...}}")
+			}
+			item, ok := m.Value["Item"].(*types.AttributeValueMemberM)
+			if !ok {
+				return nil, fmt.Errorf("invalid export data, the line must be {Item:{...}}")
+			}
+			return item.Value, nil
+		}
+		return nil
+	}
+	d := json.NewDecoder(gr)
+	r.decode = func() (map[string]types.AttributeValue, error) {
+		var line struct {
+			Item map[string]interface{} `json:"Item"`
+		}
+		if err := d.Decode(&line); err != nil {
+			return nil, err
+		}
+		if line.Item == nil {
+			return nil, fmt.Errorf("invalid export data, the line must be {\"Item\":{...}}")
+		}
+		return analyseDynamoDBJSONItem(line.Item)
+	}
+	return nil
+}
+
+func (r *awsExportItemReader) next() (map[string]types.AttributeValue, error) {
+	for {
+		if r.decode == nil {
+			if len(r.files) == 0 {
+				return nil, io.EOF
+			}
+			if err := r.open(r.files[0]); err != nil {
+				return nil, err
+			}
+			r.files = r.files[1:]
+		}
+		item, err := r.decode()
+		if err == io.EOF {
+			if err := r.close(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", r.name, err)
+		}
+		return item, nil
+	}
+}
+
+func (r *awsExportItemReader) isList() bool {
+	return true
+}
+
+// close closes the data file which is being read.
+func (r *awsExportItemReader) close() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file, r.decode = nil, nil
+	return err
+}
+
+// ScanExport reads the table export to S3 which is downloaded to dir, and shows the items
+// which match the filter condition in the same way as Scan.
+func (i *Instance) ScanExport(
+	_ context.Context,
+	w io.Writer,
+	dir string,
+	f func(string) (io.ReadCloser, error),
+	filterCondition,
+	projection,
+	output string,
+) error {
+	if len(dir) == 0 {
+		return fmt.Errorf("required --export-dir option")
+	}
+	filter, err := newLocalFilter(filterCondition)
+	if err != nil {
+		return err
+	}
+	paths, err := parseProjection(projection)
+	if err != nil {
+		return err
+	}
+	r, err := newAWSExportItemReader(dir, f)
+	if err != nil {
+		return err
+	}
+	defer r.close()
+
+	var items []map[string]types.AttributeValue
+	for {
+		item, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !filter.match(item) {
+			continue
+		}
+		items = append(items, projectItem(item, paths))
+	}
+
+	res := make([]map[string]interface{}, 0, len(items))
+	if err := attributevalue.UnmarshalListOfMaps(items, &res); err != nil {
+		return err
+	}
+	str, err := adjustSpecifiedFormat(output, res)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s", str)
+
+	return nil
+}
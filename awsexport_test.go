@@ -0,0 +1,121 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// awsExportFixture returns the files of the table export to S3 in dir export. The data files are compressed.
+func awsExportFixture(t *testing.T, format string, data ...string) func(string) (string, error) {
+	t.Helper()
+
+	files := map[string]string{
+		filepath.Join("export", "manifest-summary.json"): fmt.Sprintf(
+			`{"version":"2020-06-30","exportArn":"TEST_EXPORT_ARN","tableArn":"TEST_ARN","outputFormat":"%s",`+
+				`"manifestFilesS3Key":"prefix/AWSDynamoDB/01/manifest-files.json"}`, format),
+	}
+	var manifest bytes.Buffer
+	for i := range data {
+		name := fmt.Sprintf("data%d.json.gz", i)
+		fmt.Fprintf(&manifest, `{"itemCount":1,"dataFileS3Key":"prefix/AWSDynamoDB/01/data/%s"}`+"\n", name)
+		files[filepath.Join("export", "data", name)] = gzipFixture(t, data[i])
+	}
+	files[filepath.Join("export", "manifest-files.json")] = manifest.String()
+
+	return func(name string) (string, error) {
+		s, ok := files[name]
+		if !ok {
+			return "", fmt.Errorf("no such file: %s", name)
+		}
+		return s, nil
+	}
+}
+
+func TestInstance_ScanExport(t *testing.T) {
+	type args struct {
+		dir             string
+		f               func(string) (string, error)
+		filterCondition string
+		projection      string
+	}
+	dynamoDBJSONData := []string{
+		`{"Item":{"ID":{"N":"1"},"Name":{"S":"Alice"},"Age":{"N":"20"}}}` + "\n" +
+			`{"Item":{"ID":{"N":"2"},"Name":{"S":"Bob"},"Age":{"N":"18"}}}` + "\n",
+		`{"Item":{"ID":{"N":"3"},"Name":{"S":"Carol"},"Age":{"N":"31"},"Tags":{"SS":["a","b"]}}}` + "\n",
+	}
+	ionData := []string{
+		`$ion_1_0 {Item:{ID:1.,Name:"Alice",Age:20.}}` + "\n" +
+			`$ion_1_0 {Item:{ID:2.,Name:"Bob",Age:18.}}` + "\n",
+		`$ion_1_0 {Item:{ID:3.,Name:"Carol",Age:31.,Tags:$dynamodb_SS::["a","b"]}}` + "\n",
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantW   string
+		wantErr bool
+	}{
+		{
+			name: "Scan DynamoDB JSON export with filter and projection",
+			args: args{
+				dir:             "export",
+				f:               awsExportFixture(t, "DYNAMODB_JSON", dynamoDBJSONData...),
+				filterCondition: "Age,N >= 20",
+				projection:      "ID,Name",
+			},
+			wantW: "{\"ID\":1,\"Name\":\"Alice\"}\n{\"ID\":3,\"Name\":\"Carol\"}\n",
+		},
+		{
+			name: "Scan Ion export with filter",
+			args: args{
+				dir:             "export",
+				f:               awsExportFixture(t, "ION", ionData...),
+				filterCondition: "Tags,SS exists or Name,S = Bob",
+			},
+			wantW: "{\"Age\":18,\"ID\":2,\"Name\":\"Bob\"}\n" +
+				"{\"Age\":31,\"ID\":3,\"Name\":\"Carol\",\"Tags\":[\"a\",\"b\"]}\n",
+		},
+		{
+			name: "Scan incremental export",
+			args: args{
+				dir: "export",
+				f: func(string) (string, error) {
+					return `{"outputFormat":"DYNAMODB_JSON","exportType":"INCREMENTAL_EXPORT"}`, nil
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Scan export whose data is broken",
+			args: args{
+				dir: "export",
+				f:   awsExportFixture(t, "DYNAMODB_JSON", `{"ID":{"N":"1"}}`),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &Instance{}
+			w := &bytes.Buffer{}
+			err := i.ScanExport(
+				context.Background(),
+				w,
+				tt.args.dir,
+				fileFixture(t, tt.args.f),
+				tt.args.filterCondition,
+				tt.args.projection,
+				"jsonl",
+			)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ScanExport() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("ScanExport() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package edy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/client"
+	"github.com/hirano00o/edy/model"
+)
+
+// batchWriteItems writes requests by BatchWriteItem in chunks of the API limit,
+// and returns the requests which are still unprocessed after retrying.
+func batchWriteItems(ctx context.Context, tableName string, requests []types.WriteRequest) ([]types.WriteRequest, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	var unprocessed []types.WriteRequest
+	for start := 0; start < len(requests); start += model.BatchWriteItemMax {
+		end := start + model.BatchWriteItemMax
+		if end > len(requests) {
+			end = len(requests)
+		}
+		input := &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{
+				tableName: requests[start:end],
+			},
+		}
+		var res *dynamodb.BatchWriteItemOutput
+		var err error
+		for i := 0; i < 1+model.RetryMax; i++ {
+			res, err = cli.BatchWriteItem(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+			if len(res.UnprocessedItems[tableName]) == 0 {
+				break
+			}
+			input.RequestItems = res.UnprocessedItems
+		}
+		unprocessed = append(unprocessed, res.UnprocessedItems[tableName]...)
+	}
+	return unprocessed, nil
+}
+
+// batchWriter sends the requests by BatchWriteItem every time the requests reach the chunk size.
+type batchWriter struct {
+	ctx         context.Context
+	tableName   string
+	requests    []types.WriteRequest
+	unprocessed []types.WriteRequest
+	// written is the number of requests which have been sent.
+	written int
+}
+
+func newBatchWriter(ctx context.Context, tableName string) *batchWriter {
+	return &batchWriter{
+		ctx:       ctx,
+		tableName: tableName,
+		requests:  make([]types.WriteRequest, 0, model.BatchWriteItemMax),
+	}
+}
+
+func (b *batchWriter) add(request types.WriteRequest) error {
+	b.requests = append(b.requests, request)
+	if len(b.requests) < model.BatchWriteItemMax {
+		return nil
+	}
+	return b.flush()
+}
+
+func (b *batchWriter) flush() error {
+	if len(b.requests) == 0 {
+		return nil
+	}
+	unprocessed, err := batchWriteItems(b.ctx, b.tableName, b.requests)
+	if err != nil {
+		return fmt.Errorf("%v (%d items have been written)", err, b.written)
+	}
+	b.written += len(b.requests)
+	b.unprocessed = append(b.unprocessed, unprocessed...)
+	b.requests = make([]types.WriteRequest, 0, model.BatchWriteItemMax)
+	return nil
+}
+
+// close sends the rest of requests, and returns all unprocessed requests.
+func (b *batchWriter) close() ([]types.WriteRequest, error) {
+	if err := b.flush(); err != nil {
+		return nil, err
+	}
+	return b.unprocessed, nil
+}
+
+func unprocessedResult(unprocessed []types.WriteRequest) map[string]interface{} {
+	if len(unprocessed) > 0 {
+		return map[string]interface{}{
+			"unprocessed": unprocessed,
+		}
+	}
+	return map[string]interface{}{"unprocessed": []string{}}
+}
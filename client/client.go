@@ -13,12 +13,14 @@ import (
 type Client struct {
 	config   aws.Config
 	endpoint string
+	readOnly bool
 }
 
 type DynamoDB interface {
 	dynamodb.DescribeTableAPIClient
 	dynamodb.ScanAPIClient
 	dynamodb.QueryAPIClient
+	dynamodb.ListTablesAPIClient
 	PutItem(
 		ctx context.Context,
 		params *dynamodb.PutItemInput,
@@ -34,6 +36,61 @@ type DynamoDB interface {
 		params *dynamodb.DeleteItemInput,
 		optFns ...func(*dynamodb.Options),
 	) (*dynamodb.DeleteItemOutput, error)
+	TransactWriteItems(
+		ctx context.Context,
+		params *dynamodb.TransactWriteItemsInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.TransactWriteItemsOutput, error)
+	TransactGetItems(
+		ctx context.Context,
+		params *dynamodb.TransactGetItemsInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.TransactGetItemsOutput, error)
+	ExecuteStatement(
+		ctx context.Context,
+		params *dynamodb.ExecuteStatementInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.ExecuteStatementOutput, error)
+	BatchExecuteStatement(
+		ctx context.Context,
+		params *dynamodb.BatchExecuteStatementInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.BatchExecuteStatementOutput, error)
+	CreateTable(
+		ctx context.Context,
+		params *dynamodb.CreateTableInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.CreateTableOutput, error)
+	UpdateTimeToLive(
+		ctx context.Context,
+		params *dynamodb.UpdateTimeToLiveInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.UpdateTimeToLiveOutput, error)
+	DeleteTable(
+		ctx context.Context,
+		params *dynamodb.DeleteTableInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.DeleteTableOutput, error)
+	UpdateTable(
+		ctx context.Context,
+		params *dynamodb.UpdateTableInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.UpdateTableOutput, error)
+	DescribeTimeToLive(
+		ctx context.Context,
+		params *dynamodb.DescribeTimeToLiveInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.DescribeTimeToLiveOutput, error)
+	DescribeContinuousBackups(
+		ctx context.Context,
+		params *dynamodb.DescribeContinuousBackupsInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.DescribeContinuousBackupsOutput, error)
+	ListTagsOfResource(
+		ctx context.Context,
+		params *dynamodb.ListTagsOfResourceInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.ListTagsOfResourceOutput, error)
 }
 
 type NewClient interface {
@@ -65,6 +122,8 @@ func New(context context.Context, options map[string]string) (*Client, error) {
 			optFns = append(optFns, config.WithRegion(options[k]))
 		case "profile":
 			optFns = append(optFns, config.WithSharedConfigProfile(options[k]))
+		case "read-only":
+			cli.readOnly = options[k] == "true"
 		}
 	}
 	c, err := config.LoadDefaultConfig(context, optFns...)
@@ -75,11 +134,25 @@ func New(context context.Context, options map[string]string) (*Client, error) {
 	return cli, nil
 }
 
+// Target describes where to connect, such as the region or the endpoint.
+func (c *Client) Target() string {
+	if len(c.endpoint) != 0 {
+		return fmt.Sprintf("endpoint %s", c.endpoint)
+	}
+	return fmt.Sprintf("region %s", c.config.Region)
+}
+
 func (c *Client) CreateInstance() DynamoDB {
+	var cli DynamoDB
 	if len(c.endpoint) != 0 {
-		return dynamodb.NewFromConfig(c.config,
+		cli = dynamodb.NewFromConfig(c.config,
 			dynamodb.WithEndpointResolver(dynamodb.EndpointResolverFromURL(c.endpoint)),
 		)
+	} else {
+		cli = dynamodb.NewFromConfig(c.config)
+	}
+	if c.readOnly {
+		return &readOnlyClient{DynamoDB: cli}
 	}
-	return dynamodb.NewFromConfig(c.config)
+	return cli
 }
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+var ErrReadOnly = errors.New("read-only mode")
+
+// readOnlyClient refuses the write APIs.
+// The write API added to DynamoDB interface must be overridden here too.
+type readOnlyClient struct {
+	DynamoDB
+}
+
+func readOnlyError(operation string) error {
+	return fmt.Errorf("%w: %s is not allowed", ErrReadOnly, operation)
+}
+
+func (readOnlyClient) PutItem(
+	context.Context,
+	*dynamodb.PutItemInput,
+	...func(*dynamodb.Options),
+) (*dynamodb.PutItemOutput, error) {
+	return nil, readOnlyError("PutItem")
+}
+
+func (readOnlyClient) BatchWriteItem(
+	context.Context,
+	*dynamodb.BatchWriteItemInput,
+	...func(*dynamodb.Options),
+) (*dynamodb.BatchWriteItemOutput, error) {
+	return nil, readOnlyError("BatchWriteItem")
+}
+
+func (readOnlyClient) DeleteItem(
+	context.Context,
+	*dynamodb.DeleteItemInput,
+	...func(*dynamodb.Options),
+) (*dynamodb.DeleteItemOutput, error) {
+	return nil, readOnlyError("DeleteItem")
+}
+
+func (readOnlyClient) TransactWriteItems(
+	context.Context,
+	*dynamodb.TransactWriteItemsInput,
+	...func(*dynamodb.Options),
+) (*dynamodb.TransactWriteItemsOutput, error) {
+	return nil, readOnlyError("TransactWriteItems")
+}
+
+func (readOnlyClient) CreateTable(
+	context.Context,
+	*dynamodb.CreateTableInput,
+	...func(*dynamodb.Options),
+) (*dynamodb.CreateTableOutput, error) {
+	return nil, readOnlyError("CreateTable")
+}
+
+func (readOnlyClient) UpdateTimeToLive(
+	context.Context,
+	*dynamodb.UpdateTimeToLiveInput,
+	...func(*dynamodb.Options),
+) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	return nil, readOnlyError("UpdateTimeToLive")
+}
+
+func (readOnlyClient) DeleteTable(
+	context.Context,
+	*dynamodb.DeleteTableInput,
+	...func(*dynamodb.Options),
+) (*dynamodb.DeleteTableOutput, error) {
+	return nil, readOnlyError("DeleteTable")
+}
+
+func (readOnlyClient) UpdateTable(
+	context.Context,
+	*dynamodb.UpdateTableInput,
+	...func(*dynamodb.Options),
+) (*dynamodb.UpdateTableOutput, error) {
+	return nil, readOnlyError("UpdateTable")
+}
+
+// IsReadStatement reports whether the PartiQL statement only reads, that is SELECT.
+func IsReadStatement(statement string) bool {
+	f := strings.Fields(statement)
+	return len(f) != 0 && strings.EqualFold(f[0], "SELECT")
+}
+
+func (c readOnlyClient) ExecuteStatement(
+	ctx context.Context,
+	params *dynamodb.ExecuteStatementInput,
+	optFns ...func(*dynamodb.Options),
+) (*dynamodb.ExecuteStatementOutput, error) {
+	if !IsReadStatement(aws.ToString(params.Statement)) {
+		return nil, readOnlyError("ExecuteStatement except SELECT")
+	}
+	return c.DynamoDB.ExecuteStatement(ctx, params, optFns...)
+}
+
+func (c readOnlyClient) BatchExecuteStatement(
+	ctx context.Context,
+	params *dynamodb.BatchExecuteStatementInput,
+	optFns ...func(*dynamodb.Options),
+) (*dynamodb.BatchExecuteStatementOutput, error) {
+	for i := range params.Statements {
+		if !IsReadStatement(aws.ToString(params.Statements[i].Statement)) {
+			return nil, readOnlyError("BatchExecuteStatement except SELECT")
+		}
+	}
+	return c.DynamoDB.BatchExecuteStatement(ctx, params, optFns...)
+}
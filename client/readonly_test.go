@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestClient_CreateInstance_readOnly(t *testing.T) {
+	c, err := New(context.Background(), map[string]string{"read-only": "true", "region": "us-east-1"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	cli := c.CreateInstance()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "PutItem",
+			call: func() error {
+				_, err := cli.PutItem(context.Background(), &dynamodb.PutItemInput{})
+				return err
+			},
+		},
+		{
+			name: "BatchWriteItem",
+			call: func() error {
+				_, err := cli.BatchWriteItem(context.Background(), &dynamodb.BatchWriteItemInput{})
+				return err
+			},
+		},
+		{
+			name: "DeleteItem",
+			call: func() error {
+				_, err := cli.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{})
+				return err
+			},
+		},
+		{
+			name: "TransactWriteItems",
+			call: func() error {
+				_, err := cli.TransactWriteItems(context.Background(), &dynamodb.TransactWriteItemsInput{})
+				return err
+			},
+		},
+		{
+			name: "ExecuteStatement",
+			call: func() error {
+				_, err := cli.ExecuteStatement(context.Background(), &dynamodb.ExecuteStatementInput{
+					Statement: aws.String("DELETE FROM User WHERE ID = 1"),
+				})
+				return err
+			},
+		},
+		{
+			name: "BatchExecuteStatement",
+			call: func() error {
+				_, err := cli.BatchExecuteStatement(context.Background(), &dynamodb.BatchExecuteStatementInput{
+					Statements: []types.BatchStatementRequest{
+						{Statement: aws.String("SELECT * FROM User WHERE ID = 1")},
+						{Statement: aws.String("update User SET Name = 'Alice' WHERE ID = 1")},
+					},
+				})
+				return err
+			},
+		},
+		{
+			name: "CreateTable",
+			call: func() error {
+				_, err := cli.CreateTable(context.Background(), &dynamodb.CreateTableInput{})
+				return err
+			},
+		},
+		{
+			name: "UpdateTimeToLive",
+			call: func() error {
+				_, err := cli.UpdateTimeToLive(context.Background(), &dynamodb.UpdateTimeToLiveInput{})
+				return err
+			},
+		},
+		{
+			name: "DeleteTable",
+			call: func() error {
+				_, err := cli.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{})
+				return err
+			},
+		},
+		{
+			name: "UpdateTable",
+			call: func() error {
+				_, err := cli.UpdateTable(context.Background(), &dynamodb.UpdateTableInput{})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, ErrReadOnly) {
+				t.Errorf("%s() error = %v, want %v", tt.name, err, ErrReadOnly)
+			}
+		})
+	}
+}
+
+func TestIsReadStatement(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      bool
+	}{
+		{statement: "SELECT * FROM User", want: true},
+		{statement: "  select ID FROM User", want: true},
+		{statement: "INSERT INTO User VALUE {'ID': 1}", want: false},
+		{statement: "SELECTED", want: false},
+		{statement: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := IsReadStatement(tt.statement); got != tt.want {
+			t.Errorf("IsReadStatement(%q) = %v, want %v", tt.statement, got, tt.want)
+		}
+	}
+}
@@ -1,8 +1,9 @@
 package main
 
 import (
+	"bufio"
+	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
 	"os"
 
@@ -34,6 +35,40 @@ var baseOptions = []cli.Flag{
 		Usage: "Port number or full URL if you connect such as dynamodb-local and LocalStack.\n" +
 			"\tex. --local 8000",
 	},
+	&cli.BoolFlag{
+		Name:    "read-only",
+		Usage:   "Refuse any write to DynamoDB.",
+		EnvVars: []string{"EDY_READ_ONLY"},
+	},
+}
+
+// connectionOptions are baseOptions without --table-name, which are used by the commands across tables.
+var connectionOptions = baseOptions[1:]
+
+var writeOptions = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print the requests which would be sent instead of writing.",
+	},
+}
+
+var confirmOptions = []cli.Flag{
+	&cli.BoolFlag{
+		Name:    "yes",
+		Usage:   "Run without confirmation.",
+		Aliases: []string{"y"},
+	},
+	&cli.StringSliceFlag{
+		Name: "protected-tables",
+		Usage: "Table name patterns which require typing the table name to confirm.\n" +
+			"\tex. --protected-tables \"prod-*,billing\"",
+		EnvVars: []string{"EDY_PROTECTED_TABLES"},
+	},
+	&cli.StringSliceFlag{
+		Name:    "protected-profiles",
+		Usage:   "AWS profile name patterns whose tables require typing the table name to confirm.",
+		EnvVars: []string{"EDY_PROTECTED_PROFILES"},
+	},
 }
 
 var queryOptions = []cli.Flag{
@@ -75,7 +110,26 @@ var scanQueryOptions = []cli.Flag{
 	&cli.StringFlag{
 		Name: "output",
 		Usage: "Output format to show the result.\n" +
-			"\tAvailable format is JSON, csv. Default is JSON",
+			"\tAvailable format is JSON, JSONL, csv. Default is JSON",
+		Aliases: []string{"o"},
+	},
+}
+
+var scanExportOptions = []cli.Flag{
+	&cli.StringFlag{
+		Name: "export-dir",
+		Usage: "The directory of the table export to S3 which has manifest-summary.json.\n" +
+			"\tex. --export-dir ./AWSDynamoDB/01234567890123-abcdefgh",
+		Aliases:  []string{"d"},
+		Required: true,
+	},
+}
+
+var describeOptions = []cli.Flag{
+	&cli.StringFlag{
+		Name: "output",
+		Usage: "Output format to show the result.\n" +
+			"\tAvailable format is JSON, YAML, table. Default is JSON",
 		Aliases: []string{"o"},
 	},
 }
@@ -88,10 +142,58 @@ var putOptions = []cli.Flag{
 		Aliases: []string{"i"},
 	},
 	&cli.StringFlag{
-		Name:    "input-file",
-		Usage:   "Read item to put from json file. Use either the --item option or this option.",
+		Name: "input-file",
+		Usage: "Read item to put from json, json lines or csv file. Use either the --item option or this option.\n" +
+			"\tRead from stdin if - is specified.",
 		Aliases: []string{"I"},
 	},
+	&cli.StringFlag{
+		Name: "input-format",
+		Usage: "Format of --item or --input-file.\n" +
+			"\tAvailable format is json, jsonl, dynamodb-json, csv. Default is detected from the input and the file extension.\n" +
+			"\taws-export reads the directory of the table export to S3 which is specified by --input-file.\n" +
+			"\tex. --input-format dynamodb-json --item '{\"ID\":{\"N\":\"3\"},\"Name\":{\"S\":\"Alice\"}}'",
+	},
+	&cli.StringFlag{
+		Name: "array-type",
+		Usage: "Type which JSON array of string or number is converted to.\n" +
+			"\tAvailable type is set, list. Default is set",
+	},
+	&cli.StringFlag{
+		Name: "set-attrs",
+		Usage: "Attributes whose array is converted to set regardless of --array-type.\n" +
+			"\tNested attribute is specified by dot separated path.\n" +
+			"\tex. --set-attrs \"Tags, Interest.SNS\"",
+	},
+	&cli.StringFlag{
+		Name:  "list-attrs",
+		Usage: "Attributes whose array is converted to list regardless of --array-type.",
+	},
+	&cli.StringFlag{
+		Name: "empty-cell",
+		Usage: "How the empty cell of csv is handled.\n" +
+			"\tAvailable value is skip, null. Default is skip",
+	},
+	&cli.BoolFlag{
+		Name:  "if-not-exists",
+		Usage: "Put the item only if the item of the same key does not exist.",
+	},
+	&cli.StringFlag{
+		Name: "condition",
+		Usage: "Put the item only if the existing item matches the condition.\n" +
+			"\tThe format is the same as --filter of scan and query.\n" +
+			"\tex. --condition \"Version,N = 3\"",
+	},
+	&cli.StringFlag{
+		Name: "ttl-in",
+		Usage: "Set the TTL attribute of the table to the time after the duration in epoch seconds.\n" +
+			"\tAvailable unit is w, d, h, m, s. ex. --ttl-in 7d",
+	},
+	&cli.BoolFlag{
+		Name: "skip-invalid",
+		Usage: "Put the valid items and report the items which do not match the key schema.\n" +
+			"\tWithout it, nothing is put if there is the invalid item.",
+	},
 }
 
 var deleteOptions = []cli.Flag{
@@ -106,10 +208,299 @@ var deleteOptions = []cli.Flag{
 		Aliases: []string{"s"},
 	},
 	&cli.StringFlag{
-		Name:    "input-file",
-		Usage:   "Read item to delete from json file. Use either the --partition (and --sort) option or this option.",
+		Name: "input-file",
+		Usage: "Read item to delete from json or json lines file. Use either the --partition (and --sort) option or this option.\n" +
+			"\tRead from stdin if - is specified.",
 		Aliases: []string{"I"},
 	},
+	&cli.StringFlag{
+		Name: "input-format",
+		Usage: "Format of --input-file.\n" +
+			"\tAvailable format is json, jsonl, dynamodb-json. Default is detected from the input.",
+	},
+	&cli.StringFlag{
+		Name: "where",
+		Usage: "Delete the items which match the condition. The format is the same as --filter of scan and query.\n" +
+			"\tex. --where \"Age,N >= 20\"",
+		Aliases: []string{"w"},
+	},
+	&cli.BoolFlag{
+		Name: "from-query",
+		Usage: "Delete the items found by query. --partition is the partition value, and --sort is the sort key condition.\n" +
+			"\tex. --from-query --partition tenant1 --sort \"< 2021-01-01\"",
+	},
+	&cli.StringFlag{
+		Name: "condition",
+		Usage: "Delete the item only if the item matches the condition.\n" +
+			"\tThe format is the same as --filter of scan and query.\n" +
+			"\tex. --condition \"Status,S = archived\"",
+	},
+	&cli.BoolFlag{
+		Name:  "return-old",
+		Usage: "Show the deleted items.",
+	},
+}
+
+var transactOptions = []cli.Flag{
+	&cli.StringFlag{
+		Name: "input-file",
+		Usage: "Read operations from json or json lines file. Read from stdin if - is specified.\n" +
+			"\tex. {\"put\":{\"table\":\"Users\",\"item\":{\"ID\":3,\"Name\":\"Alice\"},\"condition\":\"not ID,N exists\"}}\n" +
+			"\t    {\"update\":{\"table\":\"Counts\",\"key\":{\"Name\":\"users\"},\"set\":{\"Count\":3}}}",
+		Aliases:  []string{"I"},
+		Required: true,
+	},
+	&cli.StringFlag{
+		Name:  "client-request-token",
+		Usage: "Token to make the transaction idempotent. The retried transaction with the same token is not applied twice.",
+	},
+}
+
+var transactGetOptions = []cli.Flag{
+	&cli.StringFlag{
+		Name: "input-file",
+		Usage: "Read keys to get from json or json lines file. Read from stdin if - is specified.\n" +
+			"\tex. {\"table\":\"Order\",\"key\":{\"ID\":1},\"projection\":\"ID, Total\"}",
+		Aliases:  []string{"I"},
+		Required: true,
+	},
+	&cli.StringFlag{
+		Name: "output",
+		Usage: "Output format to show the result.\n" +
+			"\tAvailable format is JSON, JSONL, csv. Default is JSON",
+		Aliases: []string{"o"},
+	},
+}
+
+var sqlOptions = []cli.Flag{
+	&cli.StringFlag{
+		Name: "parameters",
+		Usage: "JSON array of the parameters which replace ? in the statement.\n" +
+			"\tex. edy sql --parameters '[1, \"Alice\"]' \"SELECT * FROM User WHERE ID = ? AND Name = ?\"",
+	},
+	&cli.StringFlag{
+		Name: "input-file",
+		Usage: "Read statements from json or json lines file instead of the argument, which are executed at once.\n" +
+			"\tex. {\"statement\":\"UPDATE User SET Age = ? WHERE ID = ?\",\"parameters\":[20, 1]}",
+		Aliases: []string{"I"},
+	},
+	&cli.StringFlag{
+		Name: "output",
+		Usage: "Output format to show the result.\n" +
+			"\tAvailable format is JSON, JSONL, csv. Default is JSON",
+		Aliases: []string{"o"},
+	},
+}
+
+var createTableOptions = []cli.Flag{
+	&cli.StringFlag{
+		Name: "spec-file",
+		Usage: "Read the table spec from json or yaml file, which is the same format as the output of describe.\n" +
+			"\tRead from stdin if - is specified.",
+		Required: true,
+	},
+	&cli.StringFlag{
+		Name:    "table-name",
+		Usage:   "DynamoDB table name, which overrides the name in the spec.",
+		Aliases: []string{"t"},
+	},
+}
+
+var exportOptions = []cli.Flag{
+	&cli.StringFlag{
+		Name: "output-file",
+		Usage: "Write the schema and the items to the gzip compressed file.\n" +
+			"\tWrite to stdout if - is specified.",
+		Aliases:  []string{"O"},
+		Required: true,
+	},
+}
+
+var importOptions = []cli.Flag{
+	&cli.StringFlag{
+		Name: "input-file",
+		Usage: "Read the schema and the items from the file written by export.\n" +
+			"\tRead from stdin if - is specified.",
+		Aliases:  []string{"I"},
+		Required: true,
+	},
+	&cli.StringFlag{
+		Name: "table-name",
+		Usage: "DynamoDB table name, which overrides the name in the file.\n" +
+			"\tThe table is created from the exported schema if it does not exist.",
+		Aliases: []string{"t"},
+	},
+}
+
+// endpointOptions are the connection options of the source or the target of copy and diff.
+func endpointOptions(side string, required bool) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     side + "-table",
+			Usage:    fmt.Sprintf("DynamoDB table name of the %s.", side),
+			Required: required,
+		},
+		&cli.StringFlag{
+			Name:  side + "-region",
+			Usage: fmt.Sprintf("AWS region of the %s.", side),
+		},
+		&cli.StringFlag{
+			Name:  side + "-profile",
+			Usage: fmt.Sprintf("AWS profile name of the %s.", side),
+		},
+		&cli.StringFlag{
+			Name:  side + "-local",
+			Usage: fmt.Sprintf("Port number or full URL of the %s such as dynamodb-local and LocalStack.", side),
+		},
+	}
+}
+
+var copyOptions = []cli.Flag{
+	&cli.BoolFlag{
+		Name:    "read-only",
+		Usage:   "Refuse any write to DynamoDB.",
+		EnvVars: []string{"EDY_READ_ONLY"},
+	},
+	&cli.StringFlag{
+		Name:    "partition",
+		Usage:   "Copy the items found by query with the value of partition key instead of scan.",
+		Aliases: []string{"p"},
+	},
+	&cli.StringFlag{
+		Name: "sort",
+		Usage: "The value and condition of sort key with --partition.\n" +
+			"\tex. --sort \"begins_with 2021-\"",
+		Aliases: []string{"s"},
+	},
+	&cli.StringFlag{
+		Name: "filter",
+		Usage: "Copy the items which match the condition. The format is the same as --filter of scan and query.\n" +
+			"\tex. --filter \"Age,N >= 20\"",
+		Aliases: []string{"f"},
+	},
+	&cli.IntFlag{
+		Name:  "segments",
+		Usage: "The number of segments of the source table scanned at the same time.",
+		Value: 4,
+	},
+	&cli.StringSliceFlag{
+		Name: "key-prefix",
+		Usage: "Replace the prefix of the key of type S, written as NAME:FROM:TO.\n" +
+			"\tex. --key-prefix \"TenantID:stg-:dev-\"",
+	},
+}
+
+var diffOptions = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "source-file",
+		Usage: "Read the source from the file written by export instead of --source-table.",
+	},
+	&cli.StringFlag{
+		Name:  "target-file",
+		Usage: "Read the target from the file written by export instead of --target-table.",
+	},
+	&cli.StringFlag{
+		Name: "output",
+		Usage: "Output format to show the result.\n" +
+			"\tAvailable format is JSON, unified. Default is JSON",
+		Aliases: []string{"o"},
+	},
+	&cli.StringFlag{
+		Name:  "put-file",
+		Usage: "Write the items to put into the target to make it the same as the source, which is read by put.",
+	},
+	&cli.StringFlag{
+		Name:  "delete-file",
+		Usage: "Write the keys to delete from the target to make it the same as the source, which is read by delete.",
+	},
+}
+
+var truncateOptions = []cli.Flag{
+	&cli.BoolFlag{
+		Name: "recreate",
+		Usage: "Delete and create the table from its schema instead of deleting the items. It is fast,\n" +
+			"\tbut the table gets a new ARN and stream, and the settings other than the schema are not kept.",
+	},
+}
+
+var updateTableOptions = []cli.Flag{
+	&cli.StringFlag{
+		Name: "billing-mode",
+		Usage: "Billing mode of the table.\n" +
+			"\tAvailable mode is PAY_PER_REQUEST, PROVISIONED",
+	},
+	&cli.Int64Flag{
+		Name:  "read-capacity",
+		Usage: "Provisioned read capacity units of the table.",
+	},
+	&cli.Int64Flag{
+		Name:  "write-capacity",
+		Usage: "Provisioned write capacity units of the table.",
+	},
+	&cli.StringSliceFlag{
+		Name: "gsi-capacity",
+		Usage: "Provisioned capacity units of the global secondary index, written as INDEX:READ:WRITE.\n" +
+			"\tex. --gsi-capacity EmailIndex:10:5",
+	},
+	&cli.StringFlag{
+		Name: "add-gsi",
+		Usage: "Create the global secondary index written in JSON or YAML, which is the same format as gsi of describe.\n" +
+			"\tex. --add-gsi '{\"indexName\":\"EmailIndex\",\"partitionKey\":{\"name\":\"Email\",\"type\":\"S\"}}'",
+	},
+	&cli.StringFlag{
+		Name:  "delete-gsi",
+		Usage: "Delete the global secondary index.",
+	},
+	&cli.StringFlag{
+		Name: "stream",
+		Usage: "Enable the stream with the view type, or disable it.\n" +
+			"\tAvailable value is NEW_IMAGE, OLD_IMAGE, NEW_AND_OLD_IMAGES, KEYS_ONLY, DISABLED",
+	},
+	&cli.StringFlag{
+		Name:  "table-class",
+		Usage: "Change the table class. Available value is STANDARD, STANDARD_INFREQUENT_ACCESS",
+	},
+	&cli.BoolFlag{
+		Name:  "no-wait",
+		Usage: "Return without waiting until the table and the indexes are active.",
+	},
+}
+
+var ttlOptions = []cli.Flag{
+	&cli.StringFlag{
+		Name:     "attribute-name",
+		Usage:    "The attribute which has the expiration time in epoch seconds.",
+		Required: true,
+	},
+}
+
+var listOptions = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "prefix",
+		Usage: "Show the tables whose name starts with the prefix.",
+	},
+	&cli.StringFlag{
+		Name: "match",
+		Usage: "Show the tables whose name matches the regular expression.\n" +
+			"\tex. --match \"^prod-.*-user$\"",
+		Aliases: []string{"m"},
+	},
+	&cli.BoolFlag{
+		Name:    "describe",
+		Usage:   "Show the key schema, the item count, the size, the billing mode and the status of each table.",
+		Aliases: []string{"d"},
+	},
+	&cli.IntFlag{
+		Name:  "parallel",
+		Usage: "The number of tables described at the same time with --describe.",
+		Value: 4,
+	},
+	&cli.StringFlag{
+		Name: "output",
+		Usage: "Output format to show the result.\n" +
+			"\tAvailable format is JSON, JSONL, csv. Default is JSON",
+		Aliases: []string{"o"},
+	},
 }
 
 func main() {
@@ -126,11 +517,18 @@ func run(w io.Writer, args []string) error {
 		Version: meta.Version,
 		Usage:   "Easy to use DynamoDB CLI",
 		Commands: []*cli.Command{
+			{
+				Name:    "list",
+				Usage:   "List tables",
+				Aliases: []string{"ls"},
+				Flags:   append(connectionOptions, listOptions...),
+				Action:  cmd(w),
+			},
 			{
 				Name:    "describe",
 				Usage:   "Describe table",
 				Aliases: []string{"desc"},
-				Flags:   baseOptions,
+				Flags:   append(baseOptions, describeOptions...),
 				Action:  cmd(w),
 			},
 			{
@@ -140,6 +538,12 @@ func run(w io.Writer, args []string) error {
 				Flags:   append(baseOptions, scanQueryOptions...),
 				Action:  cmd(w),
 			},
+			{
+				Name:   "scan-export",
+				Usage:  "Scan the table export to S3 which is downloaded to the local directory",
+				Flags:  append(scanExportOptions, scanQueryOptions...),
+				Action: cmd(w),
+			},
 			{
 				Name:    "query",
 				Usage:   "Query table",
@@ -151,16 +555,113 @@ func run(w io.Writer, args []string) error {
 				Name:    "put",
 				Usage:   "Put item",
 				Aliases: []string{"p"},
-				Flags:   append(baseOptions, putOptions...),
+				Flags:   append(append(append(baseOptions, writeOptions...), confirmOptions...), putOptions...),
 				Action:  cmd(w),
 			},
 			{
 				Name:    "delete",
 				Usage:   "Delete item",
 				Aliases: []string{"del"},
-				Flags:   append(baseOptions, deleteOptions...),
+				Flags:   append(append(append(baseOptions, writeOptions...), confirmOptions...), deleteOptions...),
 				Action:  cmd(w),
 			},
+			{
+				Name:   "transact",
+				Usage:  "Write items in a transaction across tables",
+				Flags:  append(append(append(connectionOptions, writeOptions...), confirmOptions...), transactOptions...),
+				Action: cmd(w),
+			},
+			{
+				Name:   "transact-get",
+				Usage:  "Get items in a transaction across tables",
+				Flags:  append(connectionOptions, transactGetOptions...),
+				Action: cmd(w),
+			},
+			{
+				Name:      "sql",
+				Usage:     "Execute PartiQL statement",
+				ArgsUsage: "STATEMENT",
+				Flags:     append(append(append(connectionOptions, writeOptions...), confirmOptions...), sqlOptions...),
+				Action:    cmd(w),
+			},
+			{
+				Name:   "create-table",
+				Usage:  "Create table from the spec and wait until it is active",
+				Flags:  append(append(connectionOptions, writeOptions...), createTableOptions...),
+				Action: cmd(w),
+			},
+			{
+				Name:   "delete-table",
+				Usage:  "Delete table and wait until it is deleted",
+				Flags:  append(append(baseOptions, writeOptions...), confirmOptions...),
+				Action: cmd(w),
+			},
+			{
+				Name:   "update-table",
+				Usage:  "Update the capacity, the billing mode, the indexes and the stream of table",
+				Flags:  append(append(baseOptions, writeOptions...), updateTableOptions...),
+				Action: cmd(w),
+			},
+			{
+				Name:  "ttl",
+				Usage: "Show, enable or disable TTL of table",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "show",
+						Usage:  "Show the TTL attribute and the status",
+						Flags:  baseOptions,
+						Action: cmd(w),
+					},
+					{
+						Name:   "enable",
+						Usage:  "Enable TTL with the attribute",
+						Flags:  append(append(baseOptions, writeOptions...), ttlOptions...),
+						Action: cmd(w),
+					},
+					{
+						Name:   "disable",
+						Usage:  "Disable TTL",
+						Flags:  append(baseOptions, writeOptions...),
+						Action: cmd(w),
+					},
+				},
+			},
+			{
+				Name:   "export",
+				Usage:  "Export the schema and all items of table to the local file",
+				Flags:  append(baseOptions, exportOptions...),
+				Action: cmd(w),
+			},
+			{
+				Name:   "import",
+				Usage:  "Import the file written by export into table, which is created if it does not exist",
+				Flags:  append(append(append(connectionOptions, writeOptions...), confirmOptions...), importOptions...),
+				Action: cmd(w),
+			},
+			{
+				Name:  "copy",
+				Usage: "Copy items between tables, which can be in another region or endpoint",
+				Flags: append(
+					append(append(endpointOptions("source", true), endpointOptions("target", true)...), writeOptions...),
+					append(confirmOptions, copyOptions...)...,
+				),
+				Action: cmd(w),
+			},
+			{
+				Name:  "diff",
+				Usage: "Show the items which differ between tables or the files written by export",
+				Flags: append(
+					append(endpointOptions("source", false), endpointOptions("target", false)...),
+					diffOptions...,
+				),
+				Action: cmd(w),
+			},
+			{
+				Name:   "truncate",
+				Usage:  "Delete all items of table",
+				Flags:  append(append(append(baseOptions, writeOptions...), confirmOptions...), truncateOptions...),
+				Action: cmd(w),
+			},
 		},
 	}
 	return app.Run(args)
@@ -168,22 +669,44 @@ func run(w io.Writer, args []string) error {
 
 func cmd(w io.Writer) cli.ActionFunc {
 	return func(ctx *cli.Context) error {
-		c, err := client.New(ctx.Context, getOptions(ctx))
+		// The client of copy connects to the source, and the client of the target is created in addition.
+		prefix := ""
+		switch ctx.Command.FullName() {
+		case "copy", "diff":
+			prefix = "source-"
+		}
+		c, err := client.New(ctx.Context, getOptions(ctx, prefix))
 		if err != nil {
 			return err
 		}
-		f := func(fileName string) (string, error) {
-			b, err := ioutil.ReadFile(fileName)
-			if err != nil {
-				return "", nil
+		f := func(fileName string) (io.ReadCloser, error) {
+			if fileName == "-" {
+				return io.NopCloser(ctx.App.Reader), nil
 			}
-			return string(b), nil
+			return os.Open(fileName)
 		}
-		switch ctx.Command.Name {
+		switch ctx.Command.FullName() {
+		case "list":
+			return newEdyClient(c, ctx).List(
+				ctx.Context,
+				w,
+				edy.ListOption{
+					Prefix:   ctx.String("prefix"),
+					Match:    ctx.String("match"),
+					Describe: ctx.Bool("describe"),
+					Parallel: ctx.Int("parallel"),
+					Output:   ctx.String("output"),
+				},
+			)
 		case "describe":
-			return newEdyClient(c).DescribeTable(ctx.Context, w, ctx.String("table-name"))
+			return newEdyClient(c, ctx).DescribeTable(
+				ctx.Context,
+				w,
+				ctx.String("table-name"),
+				ctx.String("output"),
+			)
 		case "scan":
-			return newEdyClient(c).Scan(
+			return newEdyClient(c, ctx).Scan(
 				ctx.Context,
 				w,
 				ctx.String("table-name"),
@@ -191,8 +714,18 @@ func cmd(w io.Writer) cli.ActionFunc {
 				ctx.String("projection"),
 				ctx.String("output"),
 			)
+		case "scan-export":
+			return newEdyClient(c, ctx).ScanExport(
+				ctx.Context,
+				w,
+				ctx.String("export-dir"),
+				f,
+				ctx.String("filter"),
+				ctx.String("projection"),
+				ctx.String("output"),
+			)
 		case "query":
-			return newEdyClient(c).Query(
+			return newEdyClient(c, ctx).Query(
 				ctx.Context,
 				w,
 				ctx.String("table-name"),
@@ -204,16 +737,28 @@ func cmd(w io.Writer) cli.ActionFunc {
 				ctx.String("output"),
 			)
 		case "put":
-			return newEdyClient(c).Put(
+			return newEdyClient(c, ctx).Put(
 				ctx.Context,
 				w,
 				ctx.String("table-name"),
 				ctx.String("item"),
 				ctx.String("input-file"),
 				f,
+				edy.PutOption{
+					InputFormat:  ctx.String("input-format"),
+					ArrayType:    ctx.String("array-type"),
+					SetAttrs:     ctx.String("set-attrs"),
+					ListAttrs:    ctx.String("list-attrs"),
+					EmptyCell:    ctx.String("empty-cell"),
+					IfNotExists:  ctx.Bool("if-not-exists"),
+					Condition:    ctx.String("condition"),
+					TTLIn:        ctx.String("ttl-in"),
+					SkipInvalid:  ctx.Bool("skip-invalid"),
+					Confirmation: confirmation(ctx, c),
+				},
 			)
 		case "delete":
-			return newEdyClient(c).Delete(
+			return newEdyClient(c, ctx).Delete(
 				ctx.Context,
 				w,
 				ctx.String("table-name"),
@@ -221,6 +766,176 @@ func cmd(w io.Writer) cli.ActionFunc {
 				ctx.String("sort"),
 				ctx.String("input-file"),
 				f,
+				edy.DeleteOption{
+					InputFormat:  ctx.String("input-format"),
+					Where:        ctx.String("where"),
+					FromQuery:    ctx.Bool("from-query"),
+					Confirmation: confirmation(ctx, c),
+					Condition:    ctx.String("condition"),
+					ReturnOld:    ctx.Bool("return-old"),
+				},
+			)
+		case "transact":
+			return newEdyClient(c, ctx).Transact(
+				ctx.Context,
+				w,
+				ctx.String("input-file"),
+				f,
+				edy.TransactOption{
+					ClientRequestToken: ctx.String("client-request-token"),
+					Confirmation:       confirmation(ctx, c),
+				},
+			)
+		case "transact-get":
+			return newEdyClient(c, ctx).TransactGet(
+				ctx.Context,
+				w,
+				ctx.String("input-file"),
+				f,
+				edy.TransactGetOption{
+					Output:  ctx.String("output"),
+					Warning: ctx.App.ErrWriter,
+				},
+			)
+		case "sql":
+			return newEdyClient(c, ctx).SQL(
+				ctx.Context,
+				w,
+				ctx.Args().First(),
+				ctx.String("input-file"),
+				f,
+				edy.SQLOption{
+					Parameters:   ctx.String("parameters"),
+					Output:       ctx.String("output"),
+					Confirmation: confirmation(ctx, c),
+				},
+			)
+		case "create-table":
+			return newEdyClient(c, ctx).CreateTable(
+				ctx.Context,
+				w,
+				ctx.String("table-name"),
+				ctx.String("spec-file"),
+				f,
+			)
+		case "delete-table":
+			return newEdyClient(c, ctx).DeleteTable(
+				ctx.Context,
+				w,
+				ctx.String("table-name"),
+				edy.DeleteTableOption{
+					Confirmation: confirmation(ctx, c),
+				},
+			)
+		case "truncate":
+			return newEdyClient(c, ctx).Truncate(
+				ctx.Context,
+				w,
+				ctx.String("table-name"),
+				edy.TruncateOption{
+					Confirmation: confirmation(ctx, c),
+					Recreate:     ctx.Bool("recreate"),
+					Progress:     ctx.App.ErrWriter,
+				},
+			)
+		case "ttl show":
+			return newEdyClient(c, ctx).DescribeTTL(ctx.Context, w, ctx.String("table-name"))
+		case "ttl enable":
+			return newEdyClient(c, ctx).UpdateTTL(
+				ctx.Context,
+				w,
+				ctx.String("table-name"),
+				ctx.String("attribute-name"),
+				true,
+			)
+		case "ttl disable":
+			return newEdyClient(c, ctx).UpdateTTL(ctx.Context, w, ctx.String("table-name"), "", false)
+		case "update-table":
+			return newEdyClient(c, ctx).UpdateTable(
+				ctx.Context,
+				w,
+				ctx.String("table-name"),
+				edy.UpdateTableOption{
+					BillingMode:   ctx.String("billing-mode"),
+					ReadCapacity:  ctx.Int64("read-capacity"),
+					WriteCapacity: ctx.Int64("write-capacity"),
+					GSICapacity:   ctx.StringSlice("gsi-capacity"),
+					AddGSI:        ctx.String("add-gsi"),
+					DeleteGSI:     ctx.String("delete-gsi"),
+					Stream:        ctx.String("stream"),
+					TableClass:    ctx.String("table-class"),
+					NoWait:        ctx.Bool("no-wait"),
+					Progress:      ctx.App.ErrWriter,
+				},
+			)
+		case "export":
+			return newEdyClient(c, ctx).Export(
+				ctx.Context,
+				w,
+				ctx.String("table-name"),
+				ctx.String("output-file"),
+				create(w),
+				edy.ExportOption{
+					Progress: ctx.App.ErrWriter,
+				},
+			)
+		case "import":
+			return newEdyClient(c, ctx).Import(
+				ctx.Context,
+				w,
+				ctx.String("table-name"),
+				ctx.String("input-file"),
+				f,
+				edy.ImportOption{
+					Confirmation: confirmation(ctx, c),
+					Progress:     ctx.App.ErrWriter,
+				},
+			)
+		case "copy":
+			target, err := client.New(ctx.Context, getOptions(ctx, "target-"))
+			if err != nil {
+				return err
+			}
+			conf := confirmation(ctx, target)
+			if p := ctx.String("target-profile"); len(p) != 0 {
+				conf.Profile = p
+			}
+			return newEdyClient(c, ctx).Copy(
+				ctx.Context,
+				w,
+				ctx.String("source-table"),
+				ctx.String("target-table"),
+				edy.CopyOption{
+					Target:       target,
+					Partition:    ctx.String("partition"),
+					Sort:         ctx.String("sort"),
+					Filter:       ctx.String("filter"),
+					Segments:     ctx.Int("segments"),
+					KeyPrefix:    ctx.StringSlice("key-prefix"),
+					Confirmation: conf,
+					Progress:     ctx.App.ErrWriter,
+				},
+			)
+		case "diff":
+			target, err := client.New(ctx.Context, getOptions(ctx, "target-"))
+			if err != nil {
+				return err
+			}
+			return newEdyClient(c, ctx).Diff(
+				ctx.Context,
+				w,
+				f,
+				create(w),
+				edy.DiffOption{
+					SourceTable: ctx.String("source-table"),
+					SourceFile:  ctx.String("source-file"),
+					TargetTable: ctx.String("target-table"),
+					TargetFile:  ctx.String("target-file"),
+					Target:      target,
+					Output:      ctx.String("output"),
+					PutFile:     ctx.String("put-file"),
+					DeleteFile:  ctx.String("delete-file"),
+				},
 			)
 		default:
 			return nil
@@ -228,29 +943,85 @@ func cmd(w io.Writer) cli.ActionFunc {
 	}
 }
 
-func getOptions(ctx *cli.Context) map[string]string {
+// create creates the file to write, or writes to w if - is specified.
+func create(w io.Writer) func(string) (io.WriteCloser, error) {
+	return func(fileName string) (io.WriteCloser, error) {
+		if fileName == "-" {
+			return nopWriteCloser{w}, nil
+		}
+		return os.Create(fileName)
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func prompt(ctx *cli.Context) func(string) (string, error) {
+	return func(message string) (string, error) {
+		fmt.Fprint(ctx.App.ErrWriter, message)
+		answer, err := bufio.NewReader(ctx.App.Reader).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		return answer, nil
+	}
+}
+
+func confirmation(ctx *cli.Context, c *client.Client) edy.Confirmation {
+	interactive := false
+	if f, ok := ctx.App.Reader.(*os.File); ok {
+		if fi, err := f.Stat(); err == nil {
+			interactive = fi.Mode()&os.ModeCharDevice != 0
+		}
+	}
+	profile := ctx.String("profile")
+	if len(profile) == 0 {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	return edy.Confirmation{
+		Yes:               ctx.Bool("yes"),
+		Interactive:       interactive,
+		Prompt:            prompt(ctx),
+		Target:            c.Target(),
+		Profile:           profile,
+		ProtectedTables:   ctx.StringSlice("protected-tables"),
+		ProtectedProfiles: ctx.StringSlice("protected-profiles"),
+	}
+}
+
+// getOptions returns the connection options. prefix is source- or target- for copy.
+func getOptions(ctx *cli.Context, prefix string) map[string]string {
 	o := make(map[string]string)
 
 	// Get endpoint url.
-	if p := ctx.String("local"); len(p) != 0 {
+	if p := ctx.String(prefix + "local"); len(p) != 0 {
 		o["local"] = p
 	}
 
 	// Get region.
-	if r := ctx.String("region"); len(r) != 0 {
+	if r := ctx.String(prefix + "region"); len(r) != 0 {
 		o["region"] = r
 	}
 
 	// Get profile.
-	if p := ctx.String("profile"); len(p) != 0 {
+	if p := ctx.String(prefix + "profile"); len(p) != 0 {
 		o["profile"] = p
 	}
 
+	// Get read-only mode.
+	if ctx.Bool("read-only") {
+		o["read-only"] = "true"
+	}
+
 	return o
 }
 
-func newEdyClient(c client.NewClient) edy.Edy {
+func newEdyClient(c client.NewClient, ctx *cli.Context) edy.Edy {
 	return &edy.Instance{
 		NewClient: c,
+		DryRun:    ctx.Bool("dry-run"),
 	}
 }
@@ -0,0 +1,151 @@
+package edy
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// confirmSampleMax is the number of the keys shown in the confirmation.
+const confirmSampleMax = 5
+
+// Confirmation asks the user before the destructive operation.
+type Confirmation struct {
+	// Yes skips the confirmation.
+	Yes bool
+	// Interactive is true if the user can answer the prompt, such as stdin is a terminal.
+	Interactive bool
+	// Prompt shows the message and returns the answer of the user.
+	Prompt func(message string) (string, error)
+	// Target describes where to connect, such as the region or the endpoint.
+	Target string
+	// Profile is the AWS profile name to connect.
+	Profile string
+	// ProtectedTables and ProtectedProfiles are the patterns such as prod-*.
+	// The table which matches them requires typing the table name to confirm.
+	ProtectedTables   []string
+	ProtectedProfiles []string
+}
+
+func matchPatterns(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.TrimSpace(p), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Confirmation) isProtected(tableName string) bool {
+	return matchPatterns(c.ProtectedTables, tableName) ||
+		(len(c.Profile) != 0 && matchPatterns(c.ProtectedProfiles, c.Profile))
+}
+
+// needsPrompt reports whether the user is asked.
+func (c *Confirmation) needsPrompt() bool {
+	return !c.Yes && c.Interactive && c.Prompt != nil
+}
+
+// confirm asks the user whether to delete the items. The confirmation is skipped if the user cannot answer,
+// unless required is true or the table is protected.
+func (c *Confirmation) confirm(tableName string, keys []map[string]types.AttributeValue, required bool) error {
+	if c.Yes {
+		return nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d items will be deleted from %s", len(keys), tableName)
+	if len(c.Target) != 0 {
+		fmt.Fprintf(&sb, " (%s)", c.Target)
+	}
+	sb.WriteString(".\n")
+	n := len(keys)
+	if n > confirmSampleMax {
+		n = confirmSampleMax
+	}
+	for i := 0; i < n; i++ {
+		var v map[string]interface{}
+		if err := attributevalue.UnmarshalMap(keys[i], &v); err != nil {
+			return err
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&sb, "  %s\n", string(b))
+	}
+	if len(keys) > n {
+		fmt.Fprintf(&sb, "  ... and %d more\n", len(keys)-n)
+	}
+	return c.ask(tableName, sb.String(), required)
+}
+
+// confirmTable asks the user whether to run the operation of the whole table such as "delete".
+// The user always has to answer or use --yes.
+func (c *Confirmation) confirmTable(tableName, operation string) error {
+	if c.Yes {
+		return nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s will be %s", tableName, operation)
+	if len(c.Target) != 0 {
+		fmt.Fprintf(&sb, " (%s)", c.Target)
+	}
+	sb.WriteString(".\n")
+	return c.ask(tableName, sb.String(), true)
+}
+
+// confirmWrite asks the user whether to write the items such as "copied" into the protected table.
+// The other tables are written without confirmation, because the write does not delete the table.
+func (c *Confirmation) confirmWrite(tableName, operation string) error {
+	if c.Yes || !c.isProtected(tableName) {
+		return nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Items will be %s into %s", operation, tableName)
+	if len(c.Target) != 0 {
+		fmt.Fprintf(&sb, " (%s)", c.Target)
+	}
+	sb.WriteString(".\n")
+	return c.ask(tableName, sb.String(), false)
+}
+
+// ask shows the summary of the operation and asks the user.
+func (c *Confirmation) ask(tableName, summary string, required bool) error {
+	protected := c.isProtected(tableName)
+	if !c.needsPrompt() {
+		switch {
+		case protected:
+			return fmt.Errorf("%s is protected, use --yes to run without confirmation", tableName)
+		case required:
+			return fmt.Errorf("confirmation is required, use --yes to run without confirmation")
+		default:
+			return nil
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(summary)
+	if protected {
+		fmt.Fprintf(&sb, "%s is protected. Type the table name to confirm: ", tableName)
+	} else {
+		sb.WriteString("Are you sure? [y/N]: ")
+	}
+
+	answer, err := c.Prompt(sb.String())
+	if err != nil {
+		return err
+	}
+	answer = strings.TrimSpace(answer)
+	switch {
+	case protected && answer == tableName:
+		return nil
+	case !protected && (strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes")):
+		return nil
+	default:
+		return fmt.Errorf("canceled")
+	}
+}
@@ -0,0 +1,137 @@
+package edy
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestConfirmation_confirm(t *testing.T) {
+	keys := []map[string]types.AttributeValue{
+		{"ID": &types.AttributeValueMemberN{Value: "1"}},
+	}
+	answer := func(s string) func(string) (string, error) {
+		return func(string) (string, error) {
+			return s, nil
+		}
+	}
+	tests := []struct {
+		name      string
+		c         Confirmation
+		tableName string
+		required  bool
+		wantErr   bool
+	}{
+		{
+			name:      "Answer yes",
+			c:         Confirmation{Interactive: true, Prompt: answer("y\n")},
+			tableName: "TEST",
+		},
+		{
+			name:      "Answer no",
+			c:         Confirmation{Interactive: true, Prompt: answer("\n")},
+			tableName: "TEST",
+			wantErr:   true,
+		},
+		{
+			name:      "Skip confirmation if not interactive",
+			c:         Confirmation{Prompt: answer("n\n")},
+			tableName: "TEST",
+		},
+		{
+			name:      "Error confirmation is required but not interactive",
+			c:         Confirmation{Prompt: answer("y\n")},
+			tableName: "TEST",
+			required:  true,
+			wantErr:   true,
+		},
+		{
+			name:      "Skip confirmation by yes",
+			c:         Confirmation{Yes: true, ProtectedTables: []string{"prod-*"}},
+			tableName: "prod-TEST",
+			required:  true,
+		},
+		{
+			name: "Protected table requires the table name",
+			c: Confirmation{
+				Interactive:     true,
+				Prompt:          answer("prod-TEST\n"),
+				ProtectedTables: []string{"prod-*"},
+			},
+			tableName: "prod-TEST",
+		},
+		{
+			name: "Error protected table is not confirmed by y",
+			c: Confirmation{
+				Interactive:     true,
+				Prompt:          answer("y\n"),
+				ProtectedTables: []string{"prod-*"},
+			},
+			tableName: "prod-TEST",
+			wantErr:   true,
+		},
+		{
+			name: "Error protected profile is not interactive",
+			c: Confirmation{
+				Profile:           "production",
+				ProtectedProfiles: []string{"prod*"},
+			},
+			tableName: "TEST",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.c.confirm(tt.tableName, keys, tt.required); (err != nil) != tt.wantErr {
+				t.Errorf("confirm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfirmation_confirmWrite(t *testing.T) {
+	answer := func(s string) func(string) (string, error) {
+		return func(string) (string, error) {
+			return s, nil
+		}
+	}
+	tests := []struct {
+		name      string
+		c         Confirmation
+		tableName string
+		wantErr   bool
+	}{
+		{
+			name:      "Write without confirmation if not protected",
+			c:         Confirmation{Interactive: true, Prompt: answer("\n"), ProtectedTables: []string{"prod-*"}},
+			tableName: "dev-TEST",
+		},
+		{
+			name: "Protected table requires the table name",
+			c: Confirmation{
+				Interactive:     true,
+				Prompt:          answer("prod-TEST\n"),
+				ProtectedTables: []string{"prod-*"},
+			},
+			tableName: "prod-TEST",
+		},
+		{
+			name:      "Error protected table is not interactive",
+			c:         Confirmation{ProtectedTables: []string{"prod-*"}},
+			tableName: "prod-TEST",
+			wantErr:   true,
+		},
+		{
+			name:      "Skip confirmation by yes",
+			c:         Confirmation{Yes: true, ProtectedTables: []string{"prod-*"}},
+			tableName: "prod-TEST",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.c.confirmWrite(tt.tableName, "copied"); (err != nil) != tt.wantErr {
+				t.Errorf("confirmWrite() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package edy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/client"
+	"github.com/hirano00o/edy/model"
+)
+
+const defaultCopySegments = 4
+
+// sendItems passes the items of a page to the writer.
+type sendItems func([]map[string]types.AttributeValue) error
+
+// copyItemReader reads the items of the source table, which are found by the parallel scan or query.
+type copyItemReader struct {
+	items  chan map[string]types.AttributeValue
+	cancel context.CancelFunc
+	once   sync.Once
+	// err is the first error of the readers, which is set before items is closed.
+	err       error
+	transform func(map[string]types.AttributeValue) (map[string]types.AttributeValue, error)
+}
+
+func (r *copyItemReader) send(ctx context.Context, items []map[string]types.AttributeValue) error {
+	for i := range items {
+		select {
+		case r.items <- items[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (r *copyItemReader) fail(err error) {
+	r.once.Do(func() {
+		r.err = err
+		r.cancel()
+	})
+}
+
+func scanSegment(ctx context.Context, input *dynamodb.ScanInput, send sendItems) error {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	paginator := dynamodb.NewScanPaginator(cli, input)
+	for paginator.HasMorePages() {
+		res, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		if err := send(res.Items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func queryPages(ctx context.Context, input *dynamodb.QueryInput, send sendItems) error {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	paginator := dynamodb.NewQueryPaginator(cli, input)
+	for paginator.HasMorePages() {
+		res, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		if err := send(res.Items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newCopyItemReader starts reading the source table by query if the partition value is specified,
+// otherwise by scan in option.Segments segments at the same time.
+func newCopyItemReader(ctx context.Context, table *model.Table, option CopyOption) (*copyItemReader, error) {
+	var readers []func(context.Context, sendItems) error
+	if len(option.Partition) != 0 {
+		input, err := queryInput(table, table.Name, option.Partition, option.Sort, option.Filter, "", "")
+		if err != nil {
+			return nil, err
+		}
+		readers = append(readers, func(ctx context.Context, send sendItems) error {
+			return queryPages(ctx, input, send)
+		})
+	} else {
+		segments := option.Segments
+		if segments == 0 {
+			segments = defaultCopySegments
+		}
+		if segments < 1 {
+			return nil, fmt.Errorf("invalid segments, it must be 1 or more: %d", segments)
+		}
+		for n := 0; n < segments; n++ {
+			input, err := scanInput(table.Name, option.Filter, "")
+			if err != nil {
+				return nil, err
+			}
+			if segments > 1 {
+				input.Segment = aws.Int32(int32(n))
+				input.TotalSegments = aws.Int32(int32(segments))
+			}
+			readers = append(readers, func(ctx context.Context, send sendItems) error {
+				return scanSegment(ctx, input, send)
+			})
+		}
+	}
+	transform, err := keyTransform(table, option)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	r := &copyItemReader{
+		items:     make(chan map[string]types.AttributeValue, model.BatchWriteItemMax),
+		cancel:    cancel,
+		transform: transform,
+	}
+	var wg sync.WaitGroup
+	for n := range readers {
+		wg.Add(1)
+		go func(read func(context.Context, sendItems) error) {
+			defer wg.Done()
+			err := read(ctx, func(items []map[string]types.AttributeValue) error {
+				return r.send(ctx, items)
+			})
+			if err != nil {
+				r.fail(err)
+			}
+		}(readers[n])
+	}
+	go func() {
+		wg.Wait()
+		close(r.items)
+	}()
+	return r, nil
+}
+
+func (r *copyItemReader) next() (map[string]types.AttributeValue, error) {
+	item, ok := <-r.items
+	if !ok {
+		if r.err != nil {
+			return nil, r.err
+		}
+		return nil, io.EOF
+	}
+	if r.transform != nil {
+		return r.transform(item)
+	}
+	return item, nil
+}
+
+func (r *copyItemReader) isList() bool {
+	return true
+}
+
+// close stops reading the source table, and waits until the readers finish.
+func (r *copyItemReader) close() {
+	r.cancel()
+	for range r.items {
+	}
+}
+
+// keyTransform returns the function which rewrites the key of the item by option.KeyPrefix and
+// option.TransformKey, or nil if neither is specified.
+func keyTransform(
+	table *model.Table,
+	option CopyOption,
+) (func(map[string]types.AttributeValue) (map[string]types.AttributeValue, error), error) {
+	type keyPrefix struct {
+		name, from, to string
+	}
+	prefixes := make([]keyPrefix, 0, len(option.KeyPrefix))
+	for _, s := range option.KeyPrefix {
+		p := strings.SplitN(s, ":", 3)
+		if len(p) != 3 {
+			return nil, fmt.Errorf("invalid key prefix, it must be NAME:FROM:TO: %s", s)
+		}
+		var key *model.Key
+		if table.PartitionKey.Name == p[0] {
+			key = table.PartitionKey
+		} else if table.SortKey != nil && table.SortKey.Name == p[0] {
+			key = table.SortKey
+		}
+		if key == nil {
+			return nil, fmt.Errorf("%s is not the key of %s", p[0], table.Name)
+		}
+		if key.TypeStr != "S" {
+			return nil, fmt.Errorf("key prefix can be replaced only in type S, but %s is %s", key.Name, key.TypeStr)
+		}
+		prefixes = append(prefixes, keyPrefix{name: p[0], from: p[1], to: p[2]})
+	}
+	if len(prefixes) == 0 && option.TransformKey == nil {
+		return nil, nil
+	}
+
+	return func(item map[string]types.AttributeValue) (map[string]types.AttributeValue, error) {
+		key, err := keyFromItem(table, item)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range prefixes {
+			v, ok := key[p.name].(*types.AttributeValueMemberS)
+			if !ok || !strings.HasPrefix(v.Value, p.from) {
+				continue
+			}
+			key[p.name] = &types.AttributeValueMemberS{Value: p.to + strings.TrimPrefix(v.Value, p.from)}
+		}
+		if option.TransformKey != nil {
+			key, err = option.TransformKey(key)
+			if err != nil {
+				return nil, err
+			}
+		}
+		res := make(map[string]types.AttributeValue, len(item))
+		for k, v := range item {
+			res[k] = v
+		}
+		for k, v := range key {
+			res[k] = v
+		}
+		return res, nil
+	}, nil
+}
+
+// Copy writes the items of the source table to the target table, which can be in another region or endpoint.
+// The target table is created from the schema of the source table if it does not exist.
+func (i *Instance) Copy(ctx context.Context, w io.Writer, sourceTable, targetTable string, option CopyOption) error {
+	switch {
+	case len(sourceTable) == 0:
+		return fmt.Errorf("required --source-table option")
+	case len(targetTable) == 0:
+		return fmt.Errorf("required --target-table option")
+	case len(option.Partition) == 0 && len(option.Sort) != 0:
+		return fmt.Errorf("required --partition option with --sort")
+	}
+	if !i.DryRun {
+		if err := option.confirmWrite(targetTable, "copied"); err != nil {
+			return err
+		}
+	}
+	src := i.NewClient.CreateInstance()
+	srcCtx := context.WithValue(ctx, newClientKey, src)
+	table, err := describeTable(srcCtx, sourceTable)
+	if err != nil {
+		return err
+	}
+
+	r, err := newCopyItemReader(srcCtx, table, option)
+	if err != nil {
+		return err
+	}
+	defer r.close()
+
+	target := &Instance{NewClient: option.Target, DryRun: i.DryRun}
+	if target.NewClient == nil {
+		target.NewClient = i.NewClient
+	}
+	ctx = context.WithValue(ctx, newClientKey, target.createInstance(w))
+	spec := *table
+	spec.Name = targetTable
+	err = prepareImportTable(ctx, &spec, fmt.Sprintf("the source table %s", sourceTable), i.DryRun, option.Progress)
+	if err != nil {
+		return err
+	}
+	res, err := writeItems(ctx, targetTable, r, "copied", option.Progress)
+	if err != nil {
+		return err
+	}
+	return printJSON(w, res)
+}
@@ -0,0 +1,280 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/hirano00o/edy/mocks"
+)
+
+func TestInstance_Copy(t *testing.T) {
+	type args struct {
+		ctx         context.Context
+		sourceTable string
+		targetTable string
+		dryRun      bool
+		option      CopyOption
+	}
+	segment := func(n int32) interface{} {
+		return mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return input.Segment != nil && *input.Segment == n && *input.TotalSegments == 2
+		})
+	}
+	sourceMock := func(t *testing.T) *mocks.MockDynamoDBAPI {
+		t.Helper()
+
+		m := new(mocks.MockDynamoDBAPI)
+		m.On("CreateInstance").Return(m)
+		m.DescribeTableAPIClient.On("DescribeTable", mock.Anything, &dynamodb.DescribeTableInput{
+			TableName: aws.String("TEST"),
+		}).Return(activeDescribeTableOutputFixture(t), nil)
+		return m
+	}
+	batchWriteItemInput := func(items ...map[string]types.AttributeValue) *dynamodb.BatchWriteItemInput {
+		requests := make([]types.WriteRequest, 0, len(items))
+		for i := range items {
+			requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: items[i]}})
+		}
+		return &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{"TEST2": requests},
+		}
+	}
+	tests := []struct {
+		name         string
+		args         args
+		mocking      func(t *testing.T, ctx context.Context) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI)
+		wantW        string
+		wantProgress string
+		wantErr      bool
+	}{
+		{
+			name: "Copy items by parallel scan",
+			args: args{
+				ctx:         context.Background(),
+				sourceTable: "TEST",
+				targetTable: "TEST2",
+				option:      CopyOption{Segments: 2},
+			},
+			mocking: func(t *testing.T, ctx context.Context) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				src := sourceMock(t)
+				src.ScanAPIClient.On("Scan", mock.Anything, segment(0)).Return(&dynamodb.ScanOutput{
+					Items: exportItemsFixture,
+				}, nil)
+				src.ScanAPIClient.On("Scan", mock.Anything, segment(1)).Return(&dynamodb.ScanOutput{}, nil)
+
+				dst := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, dst)
+				dst.On("CreateInstance").Return(dst)
+				dst.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST2"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+				dst.BatchWriteItemClient.On("BatchWriteItem", ctx, batchWriteItemInput(exportItemsFixture...)).
+					Return(&dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]types.WriteRequest{}}, nil)
+
+				return src, dst
+			},
+			wantW:        "{\n  \"copied\": 2,\n  \"unprocessed\": []\n}\n",
+			wantProgress: "2 items have been copied into TEST2\n",
+		},
+		{
+			name: "Copy items found by query with key prefix and transform",
+			args: args{
+				ctx:         context.Background(),
+				sourceTable: "TEST",
+				targetTable: "TEST2",
+				option: CopyOption{
+					Partition: "P1",
+					KeyPrefix: []string{"TEST_PARTITION_ATTRIBUTE:P:dev-"},
+					TransformKey: func(key map[string]types.AttributeValue) (map[string]types.AttributeValue, error) {
+						key["TEST_SORT_ATTRIBUTE"] = &types.AttributeValueMemberS{Value: "COPY"}
+						return key, nil
+					},
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				src := sourceMock(t)
+				src.QueryAPIClient.On("Query", mock.Anything, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+					return aws.ToString(input.TableName) == "TEST" && input.KeyConditionExpression != nil
+				})).Return(&dynamodb.QueryOutput{
+					Items: exportItemsFixture[:1],
+				}, nil)
+
+				dst := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, dst)
+				dst.On("CreateInstance").Return(dst)
+				dst.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST2"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+				dst.BatchWriteItemClient.On("BatchWriteItem", ctx, batchWriteItemInput(map[string]types.AttributeValue{
+					"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "dev-1"},
+					"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "COPY"},
+					"TEST_ATTRIBUTE":           &types.AttributeValueMemberN{Value: "1"},
+				})).Return(&dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]types.WriteRequest{}}, nil)
+
+				return src, dst
+			},
+			wantW:        "{\n  \"copied\": 1,\n  \"unprocessed\": []\n}\n",
+			wantProgress: "1 items have been copied into TEST2\n",
+		},
+		{
+			name: "Copy into new table in dry-run mode",
+			args: args{
+				ctx:         context.Background(),
+				sourceTable: "TEST",
+				targetTable: "TEST2",
+				dryRun:      true,
+				option:      CopyOption{Segments: 1},
+			},
+			mocking: func(t *testing.T, ctx context.Context) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				src := sourceMock(t)
+				src.ScanAPIClient.On("Scan", mock.Anything, &dynamodb.ScanInput{
+					TableName: aws.String("TEST"),
+				}).Return(&dynamodb.ScanOutput{
+					Items: exportItemsFixture[:1],
+				}, nil)
+
+				dst := new(mocks.MockDynamoDBAPI)
+				dst.On("CreateInstance").Return(dst)
+				dst.DescribeTableAPIClient.On("DescribeTable", mock.Anything, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST2"),
+				}).Return(nil, &types.ResourceNotFoundException{})
+
+				return src, dst
+			},
+			wantW: jsonFixture(t, &dryRunRequest{
+				Operation: "CreateTable",
+				TableName: "TEST2",
+				Input: &dynamodb.CreateTableInput{
+					TableName: aws.String("TEST2"),
+					AttributeDefinitions: []types.AttributeDefinition{
+						{AttributeName: aws.String("TEST_PARTITION_ATTRIBUTE"), AttributeType: types.ScalarAttributeTypeS},
+						{AttributeName: aws.String("TEST_SORT_ATTRIBUTE"), AttributeType: types.ScalarAttributeTypeS},
+					},
+					KeySchema: []types.KeySchemaElement{
+						{AttributeName: aws.String("TEST_PARTITION_ATTRIBUTE"), KeyType: types.KeyTypeHash},
+						{AttributeName: aws.String("TEST_SORT_ATTRIBUTE"), KeyType: types.KeyTypeRange},
+					},
+					BillingMode: types.BillingModePayPerRequest,
+				},
+			}) + jsonFixture(t, &dryRunRequest{
+				Operation: "BatchWriteItem",
+				TableName: "TEST2",
+				Chunk:     1,
+				Count:     1,
+				Requests: []map[string]interface{}{
+					{"PutRequest": map[string]interface{}{"Item": dynamoDBJSONItem(exportItemsFixture[0])}},
+				},
+			}) + "{\n  \"copied\": 1,\n  \"unprocessed\": []\n}\n",
+			wantProgress: "Creating TEST2\n1 items have been copied into TEST2\n",
+		},
+		{
+			name: "Error scan",
+			args: args{
+				ctx:         context.Background(),
+				sourceTable: "TEST",
+				targetTable: "TEST2",
+				option:      CopyOption{Segments: 2},
+			},
+			mocking: func(t *testing.T, ctx context.Context) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				src := sourceMock(t)
+				src.ScanAPIClient.On("Scan", mock.Anything, segment(0)).Return(&dynamodb.ScanOutput{}, nil)
+				src.ScanAPIClient.On("Scan", mock.Anything, segment(1)).Return(nil, fmt.Errorf("error"))
+
+				dst := new(mocks.MockDynamoDBAPI)
+				dst.On("CreateInstance").Return(dst)
+				dst.DescribeTableAPIClient.On("DescribeTable", mock.Anything, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST2"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+
+				return src, dst
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error key prefix of the attribute which is not the key",
+			args: args{
+				ctx:         context.Background(),
+				sourceTable: "TEST",
+				targetTable: "TEST2",
+				option:      CopyOption{KeyPrefix: []string{"TEST_ATTRIBUTE:a:b"}},
+			},
+			mocking: func(t *testing.T, ctx context.Context) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				return sourceMock(t), new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error copy into protected table without confirmation",
+			args: args{
+				ctx:         context.Background(),
+				sourceTable: "TEST",
+				targetTable: "prod-TEST",
+				option: CopyOption{
+					Confirmation: Confirmation{ProtectedTables: []string{"prod-*"}},
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				return new(mocks.MockDynamoDBAPI), new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error sort without partition",
+			args: args{
+				ctx:         context.Background(),
+				sourceTable: "TEST",
+				targetTable: "TEST2",
+				option:      CopyOption{Sort: "= S1"},
+			},
+			mocking: func(t *testing.T, ctx context.Context) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				return new(mocks.MockDynamoDBAPI), new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, dst := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: src,
+				DryRun:    tt.args.dryRun,
+			}
+			w := &bytes.Buffer{}
+			progress := &bytes.Buffer{}
+			tt.args.option.Target = dst
+			tt.args.option.Progress = progress
+			err := i.Copy(tt.args.ctx, w, tt.args.sourceTable, tt.args.targetTable, tt.args.option)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Copy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("Copy() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			if gotProgress := progress.String(); gotProgress != tt.wantProgress {
+				t.Errorf("Copy() gotProgress = %v, want %v", gotProgress, tt.wantProgress)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package edy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gopkg.in/yaml.v3"
+
+	"github.com/hirano00o/edy/client"
+	"github.com/hirano00o/edy/model"
+)
+
+// decodeSpec decodes the spec written in JSON or YAML to v by the JSON tags.
+func decodeSpec(b []byte, v interface{}) error {
+	// YAML is a superset of JSON, so both are read as YAML and decoded by the JSON tags.
+	var y interface{}
+	if err := yaml.Unmarshal(b, &y); err != nil {
+		return fmt.Errorf("invalid spec format: %v", err)
+	}
+	j, err := json.Marshal(y)
+	if err != nil {
+		return fmt.Errorf("invalid spec format: %v", err)
+	}
+	if err := json.Unmarshal(j, v); err != nil {
+		return fmt.Errorf("invalid spec format: %v", err)
+	}
+	return nil
+}
+
+// readTableSpec reads the table spec written in JSON or YAML, which is the same format as the output of describe.
+func readTableSpec(r io.Reader) (*model.Table, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var t model.Table
+	if err := decodeSpec(b, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// attributeDefinitions collects the key attributes of the table and the indexes.
+type attributeDefinitions struct {
+	types map[string]string
+	defs  []types.AttributeDefinition
+}
+
+func (a *attributeDefinitions) add(k *model.Key) error {
+	if k == nil || len(k.Name) == 0 {
+		return fmt.Errorf("required key name")
+	}
+	typ := strings.ToUpper(k.TypeStr)
+	switch typ {
+	case "S", "N", "B":
+	default:
+		return fmt.Errorf("invalid key type, available type is S, N, B: %s %s", k.Name, k.TypeStr)
+	}
+	if t, ok := a.types[k.Name]; ok {
+		if t != typ {
+			return fmt.Errorf("key %s is defined as both %s and %s", k.Name, t, typ)
+		}
+		return nil
+	}
+	a.types[k.Name] = typ
+	a.defs = append(a.defs, types.AttributeDefinition{
+		AttributeName: aws.String(k.Name),
+		AttributeType: types.ScalarAttributeType(typ),
+	})
+	return nil
+}
+
+func (a *attributeDefinitions) keySchema(pk, sk *model.Key) ([]types.KeySchemaElement, error) {
+	if err := a.add(pk); err != nil {
+		return nil, fmt.Errorf("partition key: %v", err)
+	}
+	ks := []types.KeySchemaElement{
+		{AttributeName: aws.String(pk.Name), KeyType: types.KeyTypeHash},
+	}
+	if sk != nil {
+		if err := a.add(sk); err != nil {
+			return nil, fmt.Errorf("sort key: %v", err)
+		}
+		ks = append(ks, types.KeySchemaElement{AttributeName: aws.String(sk.Name), KeyType: types.KeyTypeRange})
+	}
+	return ks, nil
+}
+
+// globalSecondaryIndex makes the index from the spec. The index inherits the capacity of the table
+// if the table is provisioned, that is tableThroughput is not nil.
+func (a *attributeDefinitions) globalSecondaryIndex(
+	g *model.GlobalSecondaryIndex,
+	tableThroughput *types.ProvisionedThroughput,
+) (*types.GlobalSecondaryIndex, error) {
+	if len(g.Name) == 0 {
+		return nil, fmt.Errorf("gsi: required index name")
+	}
+	ks, err := a.keySchema(g.PartitionKey, g.SortKey)
+	if err != nil {
+		return nil, fmt.Errorf("gsi %s: %v", g.Name, err)
+	}
+	gsi := &types.GlobalSecondaryIndex{
+		IndexName:  aws.String(g.Name),
+		KeySchema:  ks,
+		Projection: projection(g.Projection),
+	}
+	if tableThroughput != nil {
+		gsi.ProvisionedThroughput = tableThroughput
+		if g.ProvisionedThroughput != nil {
+			gsi.ProvisionedThroughput = provisionedThroughput(g.ProvisionedThroughput)
+		}
+	}
+	return gsi, nil
+}
+
+func projection(p *model.Projection) *types.Projection {
+	if p == nil || len(p.Type) == 0 {
+		return &types.Projection{ProjectionType: types.ProjectionTypeAll}
+	}
+	return &types.Projection{
+		ProjectionType:   types.ProjectionType(strings.ToUpper(p.Type)),
+		NonKeyAttributes: p.NonKeyAttributes,
+	}
+}
+
+func provisionedThroughput(p *model.ProvisionedThroughput) *types.ProvisionedThroughput {
+	if p == nil {
+		return nil
+	}
+	return &types.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(p.ReadCapacityUnits),
+		WriteCapacityUnits: aws.Int64(p.WriteCapacityUnits),
+	}
+}
+
+func tableClass(s string) (types.TableClass, error) {
+	c := types.TableClass(strings.ToUpper(s))
+	for _, v := range c.Values() {
+		if c == v {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("invalid table class, available class is STANDARD, STANDARD_INFREQUENT_ACCESS: %s", s)
+}
+
+func createTableInput(spec *model.Table) (*dynamodb.CreateTableInput, error) {
+	if len(spec.Name) == 0 {
+		return nil, fmt.Errorf("required table name")
+	}
+	input := &dynamodb.CreateTableInput{
+		TableName: aws.String(spec.Name),
+	}
+	attrs := &attributeDefinitions{types: make(map[string]string)}
+	ks, err := attrs.keySchema(spec.PartitionKey, spec.SortKey)
+	if err != nil {
+		return nil, err
+	}
+	input.KeySchema = ks
+
+	mode := strings.ToUpper(spec.BillingMode)
+	if len(mode) == 0 {
+		mode = string(types.BillingModePayPerRequest)
+		if spec.ProvisionedThroughput != nil {
+			mode = string(types.BillingModeProvisioned)
+		}
+	}
+	switch types.BillingMode(mode) {
+	case types.BillingModePayPerRequest:
+	case types.BillingModeProvisioned:
+		if spec.ProvisionedThroughput == nil {
+			return nil, fmt.Errorf("required provisionedThroughput for PROVISIONED billing mode")
+		}
+		input.ProvisionedThroughput = provisionedThroughput(spec.ProvisionedThroughput)
+	default:
+		return nil, fmt.Errorf("invalid billing mode, available mode is PAY_PER_REQUEST, PROVISIONED: %s", spec.BillingMode)
+	}
+	input.BillingMode = types.BillingMode(mode)
+	if len(spec.TableClass) != 0 {
+		input.TableClass, err = tableClass(spec.TableClass)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, g := range spec.GSI {
+		gsi, err := attrs.globalSecondaryIndex(g, input.ProvisionedThroughput)
+		if err != nil {
+			return nil, err
+		}
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, *gsi)
+	}
+	for _, l := range spec.LSI {
+		ks, err := attrs.keySchema(spec.PartitionKey, l.SortKey)
+		if err != nil {
+			return nil, fmt.Errorf("lsi %s: %v", l.Name, err)
+		}
+		if l.SortKey == nil {
+			return nil, fmt.Errorf("lsi %s: required sort key", l.Name)
+		}
+		input.LocalSecondaryIndexes = append(input.LocalSecondaryIndexes, types.LocalSecondaryIndex{
+			IndexName:  aws.String(l.Name),
+			KeySchema:  ks,
+			Projection: projection(l.Projection),
+		})
+	}
+	input.AttributeDefinitions = attrs.defs
+
+	if spec.Stream != nil && len(spec.Stream.ViewType) != 0 {
+		input.StreamSpecification = &types.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: types.StreamViewType(strings.ToUpper(spec.Stream.ViewType)),
+		}
+	}
+	return input, nil
+}
+
+func createTable(ctx context.Context, spec *model.Table, wait bool) error {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	input, err := createTableInput(spec)
+	if err != nil {
+		return err
+	}
+	if _, err := cli.CreateTable(ctx, input); err != nil {
+		return err
+	}
+	if wait {
+		if err := waitTableActive(ctx, spec.Name, nil); err != nil {
+			return err
+		}
+	}
+	if spec.TTL != nil && len(spec.TTL.AttributeName) != 0 {
+		return updateTTL(ctx, spec.Name, spec.TTL.AttributeName, true)
+	}
+	return nil
+}
+
+// CreateTable creates the table from the spec file. tableName overrides the name in the spec,
+// so that the output of describe can be used to clone the table.
+func (i *Instance) CreateTable(
+	ctx context.Context,
+	w io.Writer,
+	tableName,
+	fileName string,
+	f func(string) (io.ReadCloser, error),
+) error {
+	if len(fileName) == 0 {
+		return fmt.Errorf("required --spec-file option")
+	}
+	in, err := f(fileName)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	spec, err := readTableSpec(in)
+	if err != nil {
+		return err
+	}
+	if len(tableName) != 0 {
+		spec.Name = tableName
+	}
+
+	cli := i.createInstance(w)
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	// The table is not created in dry-run mode, so there is nothing to wait for.
+	if err := createTable(ctx, spec, !i.DryRun); err != nil {
+		return err
+	}
+	if i.DryRun {
+		return nil
+	}
+
+	t, err := describeTable(ctx, spec.Name)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(t, "", strings.Repeat(" ", 2))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s\n", string(b))
+
+	return nil
+}
@@ -0,0 +1,329 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/mocks"
+	"github.com/hirano00o/edy/model"
+)
+
+func TestInstance_CreateTable(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		tableName string
+		fileName  string
+		f         func(string) (string, error)
+		dryRun    bool
+	}
+	provisionedInput := &dynamodb.CreateTableInput{
+		TableName: aws.String("TEST"),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("TEST_PARTITION_ATTRIBUTE"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("TEST_SORT_ATTRIBUTE"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("TEST_ATTRIBUTE_1"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("TEST_ATTRIBUTE_2"), AttributeType: types.ScalarAttributeTypeN},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("TEST_PARTITION_ATTRIBUTE"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("TEST_SORT_ATTRIBUTE"), KeyType: types.KeyTypeRange},
+		},
+		BillingMode: types.BillingModeProvisioned,
+		ProvisionedThroughput: &types.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(5),
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String("TEST_GSI"),
+				KeySchema: []types.KeySchemaElement{
+					{AttributeName: aws.String("TEST_ATTRIBUTE_1"), KeyType: types.KeyTypeHash},
+				},
+				Projection: &types.Projection{
+					ProjectionType:   types.ProjectionTypeInclude,
+					NonKeyAttributes: []string{"TEST_ATTRIBUTE_3"},
+				},
+				ProvisionedThroughput: &types.ProvisionedThroughput{
+					ReadCapacityUnits:  aws.Int64(1),
+					WriteCapacityUnits: aws.Int64(1),
+				},
+			},
+		},
+		LocalSecondaryIndexes: []types.LocalSecondaryIndex{
+			{
+				IndexName: aws.String("TEST_LSI"),
+				KeySchema: []types.KeySchemaElement{
+					{AttributeName: aws.String("TEST_PARTITION_ATTRIBUTE"), KeyType: types.KeyTypeHash},
+					{AttributeName: aws.String("TEST_ATTRIBUTE_2"), KeyType: types.KeyTypeRange},
+				},
+				Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			},
+		},
+		StreamSpecification: &types.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: types.StreamViewTypeNewAndOldImages,
+		},
+	}
+	provisionedSpec := `tableName: TEST
+partitionKey:
+  name: TEST_PARTITION_ATTRIBUTE
+  type: S
+sortKey:
+  name: TEST_SORT_ATTRIBUTE
+  type: S
+provisionedThroughput:
+  readCapacityUnits: 5
+  writeCapacityUnits: 5
+gsi:
+  - indexName: TEST_GSI
+    partitionKey:
+      name: TEST_ATTRIBUTE_1
+      type: S
+    projection:
+      type: INCLUDE
+      nonKeyAttributes: [TEST_ATTRIBUTE_3]
+    provisionedThroughput:
+      readCapacityUnits: 1
+      writeCapacityUnits: 1
+lsi:
+  - indexName: TEST_LSI
+    sortKey:
+      name: TEST_ATTRIBUTE_2
+      type: N
+stream:
+  viewType: NEW_AND_OLD_IMAGES
+ttl:
+  attributeName: ExpiresAt
+`
+	tests := []struct {
+		name    string
+		args    args
+		mocking func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI
+		wantW   string
+		wantErr bool
+	}{
+		{
+			name: "CreateTable from yaml spec",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "spec.yaml",
+				f: func(string) (string, error) {
+					return provisionedSpec, nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.CreateTableClient.On("CreateTable", ctx, provisionedInput).Return(&dynamodb.CreateTableOutput{}, nil)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+				m.UpdateTimeToLiveClient.On("UpdateTimeToLive", ctx, &dynamodb.UpdateTimeToLiveInput{
+					TableName: aws.String("TEST"),
+					TimeToLiveSpecification: &types.TimeToLiveSpecification{
+						AttributeName: aws.String("ExpiresAt"),
+						Enabled:       aws.Bool(true),
+					},
+				}).Return(&dynamodb.UpdateTimeToLiveOutput{}, nil)
+
+				return m
+			},
+			wantW: jsonFixture(t, model.Table{
+				Arn:    "TEST_ARN",
+				Name:   "TEST",
+				Status: "ACTIVE",
+				PartitionKey: &model.Key{
+					Name:    "TEST_PARTITION_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				SortKey: &model.Key{
+					Name:    "TEST_SORT_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				ItemCount: 1,
+			}),
+		},
+		{
+			name: "CreateTable from describe output with another name",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				fileName:  "spec.json",
+				f: func(string) (string, error) {
+					return `{
+  "tableArn": "ORIGINAL_ARN",
+  "tableName": "ORIGINAL",
+  "partitionKey": {
+    "name": "TEST_PARTITION_ATTRIBUTE",
+    "type": "S"
+  },
+  "tableClass": "STANDARD_INFREQUENT_ACCESS",
+  "itemCount": 10
+}`, nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.CreateTableClient.On("CreateTable", ctx, &dynamodb.CreateTableInput{
+					TableName: aws.String("TEST"),
+					AttributeDefinitions: []types.AttributeDefinition{
+						{AttributeName: aws.String("TEST_PARTITION_ATTRIBUTE"), AttributeType: types.ScalarAttributeTypeS},
+					},
+					KeySchema: []types.KeySchemaElement{
+						{AttributeName: aws.String("TEST_PARTITION_ATTRIBUTE"), KeyType: types.KeyTypeHash},
+					},
+					BillingMode: types.BillingModePayPerRequest,
+					TableClass:  types.TableClassStandardInfrequentAccess,
+				}).Return(&dynamodb.CreateTableOutput{}, nil)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+
+				return m
+			},
+			wantW: jsonFixture(t, model.Table{
+				Arn:    "TEST_ARN",
+				Name:   "TEST",
+				Status: "ACTIVE",
+				PartitionKey: &model.Key{
+					Name:    "TEST_PARTITION_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				SortKey: &model.Key{
+					Name:    "TEST_SORT_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				ItemCount: 1,
+			}),
+		},
+		{
+			name: "CreateTable in dry-run mode",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "spec.yaml",
+				f: func(string) (string, error) {
+					return provisionedSpec, nil
+				},
+				dryRun: true,
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+
+				return m
+			},
+			wantW: jsonFixture(t, &dryRunRequest{
+				Operation: "CreateTable",
+				TableName: "TEST",
+				Input:     provisionedInput,
+			}) + jsonFixture(t, &dryRunRequest{
+				Operation: "UpdateTimeToLive",
+				TableName: "TEST",
+				Input: &types.TimeToLiveSpecification{
+					AttributeName: aws.String("ExpiresAt"),
+					Enabled:       aws.Bool(true),
+				},
+			}),
+		},
+		{
+			name: "CreateTable with conflicting key types",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "spec.json",
+				f: func(string) (string, error) {
+					return `{"tableName":"TEST","partitionKey":{"name":"ID","type":"S"},
+"gsi":[{"indexName":"TEST_GSI","partitionKey":{"name":"ID","type":"N"}}]}`, nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "CreateTable provisioned without capacity",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "spec.json",
+				f: func(string) (string, error) {
+					return `{"tableName":"TEST","partitionKey":{"name":"ID","type":"S"},"billingMode":"PROVISIONED"}`, nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "CreateTable with invalid table class",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "spec.json",
+				f: func(string) (string, error) {
+					return `{"tableName":"TEST","partitionKey":{"name":"ID","type":"S"},"tableClass":"GLACIER"}`, nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+
+				return m
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: m,
+				DryRun:    tt.args.dryRun,
+			}
+			w := &bytes.Buffer{}
+			err := i.CreateTable(tt.args.ctx, w, tt.args.tableName, tt.args.fileName, fileFixture(t, tt.args.f))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateTable() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("CreateTable() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			m.CreateTableClient.AssertExpectations(t)
+			m.UpdateTimeToLiveClient.AssertExpectations(t)
+		})
+	}
+}
+
+func activeDescribeTableOutputFixture(t *testing.T) *dynamodb.DescribeTableOutput {
+	t.Helper()
+
+	output := describeTableOutputFixture(t, false)
+	output.Table.TableStatus = types.TableStatusActive
+	return output
+}
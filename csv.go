@@ -0,0 +1,226 @@
+package edy
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/model"
+)
+
+type emptyCellType int
+
+const (
+	skipEmptyCell emptyCellType = iota
+	nullEmptyCell
+)
+
+var emptyCellTypeMap = map[string]emptyCellType{
+	"":     skipEmptyCell,
+	"skip": skipEmptyCell,
+	"null": nullEmptyCell,
+}
+
+// csvColumnTypes are the types which can be specified in the header such as "Age:N".
+// The value of SS, NS, BS, L and M is written in JSON.
+var csvColumnTypes = map[string]struct{}{
+	"S":    {},
+	"N":    {},
+	"B":    {},
+	"BOOL": {},
+	"SS":   {},
+	"NS":   {},
+	"BS":   {},
+	"L":    {},
+	"M":    {},
+}
+
+var csvNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+type csvColumn struct {
+	name string
+	// typ is empty if the type is inferred from the value.
+	typ string
+}
+
+func convertToEmptyCellType(s string) (emptyCellType, error) {
+	if v, ok := emptyCellTypeMap[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("invalid empty cell handling: %s", s)
+}
+
+func analyseCSVHeader(header []string) ([]*csvColumn, error) {
+	columns := make([]*csvColumn, len(header))
+	names := make(map[string]struct{}, len(header))
+	for i := range header {
+		h := strings.TrimSpace(header[i])
+		if i == 0 {
+			h = strings.TrimPrefix(h, "\ufeff")
+		}
+		c := &csvColumn{name: h}
+		if j := strings.LastIndex(h, ":"); j >= 0 {
+			if _, ok := csvColumnTypes[strings.ToUpper(h[j+1:])]; ok {
+				c.name, c.typ = h[:j], strings.ToUpper(h[j+1:])
+			}
+		}
+		if len(c.name) == 0 {
+			return nil, fmt.Errorf("line 1: empty attribute name in column %d", i+1)
+		}
+		if _, ok := names[c.name]; ok {
+			return nil, fmt.Errorf("line 1: duplicate attribute name: %s", c.name)
+		}
+		names[c.name] = struct{}{}
+		columns[i] = c
+	}
+	return columns, nil
+}
+
+func inferCSVValue(v string) types.AttributeValue {
+	switch {
+	case csvNumberPattern.MatchString(v):
+		return &types.AttributeValueMemberN{Value: v}
+	case strings.EqualFold(v, "true"), strings.EqualFold(v, "false"):
+		return &types.AttributeValueMemberBOOL{Value: strings.EqualFold(v, "true")}
+	default:
+		return &types.AttributeValueMemberS{Value: v}
+	}
+}
+
+func analyseCSVValue(c *csvColumn, v string, opt *attributeOption) (types.AttributeValue, error) {
+	switch c.typ {
+	case "":
+		return inferCSVValue(v), nil
+	case "S":
+		return &types.AttributeValueMemberS{Value: v}, nil
+	case "N":
+		// ParseFloat is not used, because it accepts NaN, Inf and hex which DynamoDB rejects.
+		if !csvNumberPattern.MatchString(v) {
+			return nil, fmt.Errorf("invalid number: %s", v)
+		}
+		return &types.AttributeValueMemberN{Value: v}, nil
+	case "B":
+		return model.B{}.ConvertValueMember(v)
+	case "BOOL":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bool: %s", v)
+		}
+		return &types.AttributeValueMemberBOOL{Value: b}, nil
+	}
+
+	var j interface{}
+	if err := json.Unmarshal([]byte(v), &j); err != nil {
+		return nil, fmt.Errorf("invalid json format: %v", err)
+	}
+	switch c.typ {
+	case "SS":
+		l, ok := j.([]interface{})
+		if !ok || listType(l) != STRING {
+			return nil, fmt.Errorf("invalid SS, it must be JSON array of string: %s", v)
+		}
+		return setAttrEachType(l, c.name, &attributeOption{arrayType: setArrayType})
+	case "NS":
+		l, ok := j.([]interface{})
+		if !ok || listType(l) != FLOAT64 {
+			return nil, fmt.Errorf("invalid NS, it must be JSON array of number: %s", v)
+		}
+		return setAttrEachType(l, c.name, &attributeOption{arrayType: setArrayType})
+	case "BS":
+		return setBinaryAttr(j, c.name, model.BS{})
+	case "L":
+		l, ok := j.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid L, it must be JSON array: %s", v)
+		}
+		return listAttr(l, c.name, opt)
+	case "M":
+		m, ok := j.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid M, it must be JSON object: %s", v)
+		}
+		mm, err := recursiveAnalyseJSON(m, c.name, opt)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberM{Value: mm}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type: %s", c.typ)
+	}
+}
+
+// analyseCSV converts csv with the header row to items.
+// All invalid rows are reported together with their line numbers.
+func analyseCSV(
+	r io.Reader,
+	empty emptyCellType,
+	opt *attributeOption,
+) ([]map[string]types.AttributeValue, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("invalid csv, header row is required")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %v", err)
+	}
+	columns, err := analyseCSVHeader(header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv, %v", err)
+	}
+
+	var items []map[string]types.AttributeValue
+	var errs []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				errs = append(errs, err.Error())
+				continue
+			}
+			return nil, fmt.Errorf("invalid csv: %v", err)
+		}
+		line, _ := reader.FieldPos(0)
+		// Skip the row which has no value, such as trailing rows of spreadsheet.
+		if len(strings.Join(record, "")) == 0 {
+			continue
+		}
+
+		item := make(map[string]types.AttributeValue, len(record))
+		var rowErrs []string
+		for i := range record {
+			if len(record[i]) == 0 {
+				if empty == nullEmptyCell {
+					item[columns[i].name] = &types.AttributeValueMemberNULL{Value: true}
+				}
+				continue
+			}
+			v, err := analyseCSVValue(columns[i], record[i], opt)
+			if err != nil {
+				rowErrs = append(rowErrs, fmt.Sprintf("%s: %v", columns[i].name, err))
+				continue
+			}
+			item[columns[i].name] = v
+		}
+		if len(rowErrs) != 0 {
+			errs = append(errs, fmt.Sprintf("line %d: %s", line, strings.Join(rowErrs, ", ")))
+			continue
+		}
+		items = append(items, item)
+	}
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("invalid csv:\n%s", strings.Join(errs, "\n"))
+	}
+	return items, nil
+}
@@ -0,0 +1,142 @@
+package edy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func Test_analyseCSV(t *testing.T) {
+	type args struct {
+		csv   string
+		empty emptyCellType
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []map[string]types.AttributeValue
+		wantErr string
+	}{
+		{
+			name: "Infer types",
+			args: args{
+				csv: "ID,Name,Zip,Active,Score\n" +
+					"1,Alice,007,true,1.5\n" +
+					"2,Bob,,FALSE,\n",
+			},
+			want: []map[string]types.AttributeValue{
+				{
+					"ID":     &types.AttributeValueMemberN{Value: "1"},
+					"Name":   &types.AttributeValueMemberS{Value: "Alice"},
+					"Zip":    &types.AttributeValueMemberS{Value: "007"},
+					"Active": &types.AttributeValueMemberBOOL{Value: true},
+					"Score":  &types.AttributeValueMemberN{Value: "1.5"},
+				},
+				{
+					"ID":     &types.AttributeValueMemberN{Value: "2"},
+					"Name":   &types.AttributeValueMemberS{Value: "Bob"},
+					"Active": &types.AttributeValueMemberBOOL{Value: false},
+				},
+			},
+		},
+		{
+			name: "Typed header",
+			args: args{
+				csv: "ID:S,Age:N,Avatar:B,Tags:SS,Scores:NS,History:L,Address:M\n" +
+					"1,20,dGVzdA==,\"[\"\"a\"\",\"\"b\"\"]\",\"[1,2]\",\"[\"\"a\"\",\"\"a\"\"]\",\"{\"\"City\"\":\"\"Tokyo\"\"}\"\n",
+			},
+			want: []map[string]types.AttributeValue{
+				{
+					"ID":     &types.AttributeValueMemberS{Value: "1"},
+					"Age":    &types.AttributeValueMemberN{Value: "20"},
+					"Avatar": &types.AttributeValueMemberB{Value: []byte("test")},
+					"Tags":   &types.AttributeValueMemberSS{Value: []string{"a", "b"}},
+					"Scores": &types.AttributeValueMemberNS{Value: []string{"1", "2"}},
+					"History": &types.AttributeValueMemberL{
+						Value: []types.AttributeValue{
+							&types.AttributeValueMemberS{Value: "a"},
+							&types.AttributeValueMemberS{Value: "a"},
+						},
+					},
+					"Address": &types.AttributeValueMemberM{
+						Value: map[string]types.AttributeValue{
+							"City": &types.AttributeValueMemberS{Value: "Tokyo"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Empty cell is null and empty row is skipped",
+			args: args{
+				csv:   "\ufeffID,Name\n1,\n,\n",
+				empty: nullEmptyCell,
+			},
+			want: []map[string]types.AttributeValue{
+				{
+					"ID":   &types.AttributeValueMemberN{Value: "1"},
+					"Name": &types.AttributeValueMemberNULL{Value: true},
+				},
+			},
+		},
+		{
+			name: "Report all invalid rows with line number",
+			args: args{
+				csv: "ID:N,Active:BOOL\n" +
+					"1,true\n" +
+					"a,true\n" +
+					"3,yes\n" +
+					"4\n",
+			},
+			wantErr: "invalid csv:\n" +
+				"line 3: ID: invalid number: a\n" +
+				"line 4: Active: invalid bool: yes\n" +
+				"record on line 5: wrong number of fields",
+		},
+		{
+			name: "Report numbers which DynamoDB rejects with line number",
+			args: args{
+				csv: "ID:N\n" +
+					"NaN\n" +
+					"Inf\n" +
+					"0x1p-2\n" +
+					"1.5e3\n",
+			},
+			wantErr: "invalid csv:\n" +
+				"line 2: ID: invalid number: NaN\n" +
+				"line 3: ID: invalid number: Inf\n" +
+				"line 4: ID: invalid number: 0x1p-2",
+		},
+		{
+			name: "Duplicate header",
+			args: args{
+				csv: "ID,ID:N\n1,2\n",
+			},
+			wantErr: "invalid csv, line 1: duplicate attribute name: ID",
+		},
+		{
+			name: "No header",
+			args: args{
+				csv: "",
+			},
+			wantErr: "invalid csv, header row is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt, _ := newAttributeOption("", "", "")
+			got, err := analyseCSV(strings.NewReader(tt.args.csv), tt.args.empty, opt)
+			if err != nil || len(tt.wantErr) != 0 {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("analyseCSV() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("analyseCSV() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
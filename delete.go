@@ -3,12 +3,14 @@ package edy
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"reflect"
-	"strconv"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
@@ -19,95 +21,304 @@ import (
 type dynamoDBValue struct {
 	partitionValue string
 	sortValue      string
+	// item is set instead of partitionValue and sortValue when the input is DynamoDB JSON.
+	item map[string]types.AttributeValue
 }
 
-func deleteItems(ctx context.Context, tableName string, items []*dynamoDBValue) (map[string]interface{}, error) {
-	table, err := describeTable(ctx, tableName)
-	if err != nil {
-		return nil, err
+func keyFromItem(table *model.Table, item map[string]types.AttributeValue) (map[string]types.AttributeValue, error) {
+	m := make(map[string]types.AttributeValue)
+	v, ok := item[table.PartitionKey.Name]
+	if !ok {
+		return nil, fmt.Errorf("required partition key %s: %v", table.PartitionKey.Name, item)
 	}
-	cli := ctx.Value(newClientKey).(client.DynamoDB)
+	m[table.PartitionKey.Name] = v
+	if table.SortKey != nil {
+		v, ok := item[table.SortKey.Name]
+		if !ok {
+			return nil, fmt.Errorf("required sort key %s: %v", table.SortKey.Name, item)
+		}
+		m[table.SortKey.Name] = v
+	}
+	return m, nil
+}
 
+func deleteKey(table *model.Table, v *dynamoDBValue) (map[string]types.AttributeValue, error) {
+	if v.item != nil {
+		return keyFromItem(table, v.item)
+	}
+	var err error
+	m := make(map[string]types.AttributeValue)
 	partitionKeyName, partitionKeyType := table.PartitionKey.Name, table.PartitionKey.Type
 
-	deleteRequest := make([]types.WriteRequest, len(items))
-	for i := range items {
-		m := make(map[string]types.AttributeValue)
-
-		// PartitionKey condition
-		m[partitionKeyName] = partitionKeyType.ConvertValueMember(items[i].partitionValue)
+	// PartitionKey condition
+	m[partitionKeyName], err = partitionKeyType.ConvertValueMember(v.partitionValue)
+	if err != nil {
+		return nil, err
+	}
 
-		// SortKey condition
-		if len(items[i].sortValue) != 0 && table.SortKey != nil {
-			sortKeyName, sortKeyType := table.SortKey.Name, table.SortKey.Type
-			m[sortKeyName] = sortKeyType.ConvertValueMember(items[i].sortValue)
+	// SortKey condition
+	if table.SortKey != nil {
+		if len(v.sortValue) == 0 {
+			return nil, fmt.Errorf("required sort key %s", table.SortKey.Name)
 		}
-
-		deleteRequest[i].DeleteRequest = &types.DeleteRequest{
-			Key: m,
+		sortKeyName, sortKeyType := table.SortKey.Name, table.SortKey.Type
+		m[sortKeyName], err = sortKeyType.ConvertValueMember(v.sortValue)
+		if err != nil {
+			return nil, err
 		}
 	}
+	return m, nil
+}
 
-	input := &dynamodb.BatchWriteItemInput{
-		RequestItems: map[string][]types.WriteRequest{
-			tableName: deleteRequest,
-		},
-	}
-	var res *dynamodb.BatchWriteItemOutput
-	var unprocessedCount int
-	for i := 0; i < 1+model.RetryMax; i++ {
-		res, err = cli.BatchWriteItem(ctx, input)
+// deleteItems deletes the items by BatchWriteItem in chunks while reading.
+// head is the items which have already been read from r.
+func deleteItems(
+	ctx context.Context,
+	tableName string,
+	table *model.Table,
+	head []*dynamoDBValue,
+	r deleteItemReader,
+) (map[string]interface{}, error) {
+	bw := newBatchWriter(ctx, tableName)
+	add := func(v *dynamoDBValue) error {
+		key, err := deleteKey(table, v)
 		if err != nil {
+			return err
+		}
+		return bw.add(types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}})
+	}
+	for i := range head {
+		if err := add(head[i]); err != nil {
 			return nil, err
 		}
-		unprocessedCount = len(res.UnprocessedItems[tableName])
-		if unprocessedCount == 0 {
+	}
+	for {
+		v, err := r.next()
+		if err == io.EOF {
 			break
 		}
-		input.RequestItems = res.UnprocessedItems
+		if err != nil {
+			return nil, fmt.Errorf("%v (%d items have been deleted)", err, bw.written)
+		}
+		if err := add(v); err != nil {
+			return nil, err
+		}
 	}
-	if unprocessedCount > 0 {
-		return map[string]interface{}{
-			"unprocessed": res.UnprocessedItems[tableName],
-		}, nil
+	unprocessed, err := bw.close()
+	if err != nil {
+		return nil, err
 	}
+	return unprocessedResult(unprocessed), nil
+}
 
-	return map[string]interface{}{"unprocessed": []string{}}, nil
+// deleteItemReader reads the keys to delete one by one.
+type deleteItemReader interface {
+	// next returns io.EOF if there is no more item.
+	next() (*dynamoDBValue, error)
 }
 
-func analyseDeleteRequestItem(requestJSONStr string) ([]*dynamoDBValue, error) {
-	jsonItem, err := parseJSON(requestJSONStr)
+type jsonDeleteItemReader struct {
+	*jsonItemDecoder
+}
+
+func (r *jsonDeleteItemReader) next() (*dynamoDBValue, error) {
+	m, typed, err := r.jsonItemDecoder.next()
 	if err != nil {
 		return nil, err
 	}
+	if typed {
+		item, err := analyseDynamoDBJSONItem(m)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %v", r.count, err)
+		}
+		return &dynamoDBValue{item: item}, nil
+	}
+	v, err := getValueFromRequestItems(m)
+	if err != nil {
+		return nil, err
+	}
+	if len(v.partitionValue) == 0 {
+		return nil, fmt.Errorf("required partition value: %v", m)
+	}
+	return v, nil
+}
 
-	switch j := jsonItem.(type) {
-	case []map[string]interface{}:
-		deleteItems := make([]*dynamoDBValue, len(j))
-		for i := range j {
-			deleteItems[i], err = getValueFromRequestItems(j[i])
-			if err != nil {
-				return nil, err
-			}
-			if len(deleteItems[i].partitionValue) == 0 {
-				return nil, fmt.Errorf("required partition value: %v", jsonItem)
+type sliceDeleteItemReader struct {
+	items []*dynamoDBValue
+	i     int
+}
+
+func (r *sliceDeleteItemReader) next() (*dynamoDBValue, error) {
+	if r.i >= len(r.items) {
+		return nil, io.EOF
+	}
+	r.i++
+	return r.items[r.i-1], nil
+}
+
+func newDeleteItemReader(r io.Reader, fileName string, format inputFormatType) (deleteItemReader, error) {
+	switch detectInputFormat(format, fileName) {
+	case csvInputType:
+		return nil, fmt.Errorf("csv is not supported in delete")
+	case awsExportInputType:
+		return nil, fmt.Errorf("aws-export is not supported in delete")
+	}
+	d, err := newJSONItemDecoder(r, format)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonDeleteItemReader{jsonItemDecoder: d}, nil
+}
+
+// findDeleteKeys finds the keys of the items to delete by scan with the filter, or query.
+func findDeleteKeys(
+	ctx context.Context,
+	table *model.Table,
+	tableName,
+	partitionValue,
+	sortCondition,
+	filterCondition string,
+	fromQuery bool,
+) ([]map[string]types.AttributeValue, error) {
+	projection := table.PartitionKey.Name
+	if table.SortKey != nil {
+		projection += "," + table.SortKey.Name
+	}
+	if fromQuery {
+		input, err := queryInput(table, tableName, partitionValue, sortCondition, filterCondition, "", projection)
+		if err != nil {
+			return nil, err
+		}
+		return queryItems(ctx, input)
+	}
+	input, err := scanInput(tableName, filterCondition, projection)
+	if err != nil {
+		return nil, err
+	}
+	return scanItems(ctx, input)
+}
+
+// readDeleteKeys reads all keys ahead to show them in the confirmation.
+func readDeleteKeys(
+	table *model.Table,
+	head []*dynamoDBValue,
+	r deleteItemReader,
+) ([]*dynamoDBValue, []map[string]types.AttributeValue, error) {
+	items := head
+	for {
+		v, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, v)
+	}
+	keys := make([]map[string]types.AttributeValue, len(items))
+	for i := range items {
+		key, err := deleteKey(table, items[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		keys[i] = key
+	}
+	return items, keys, nil
+}
+
+// conditionalDeleteItems deletes the items one by one by DeleteItem, because BatchWriteItem
+// can neither use the condition nor return the deleted items.
+// The items which do not match the condition are reported instead of aborting.
+func conditionalDeleteItems(
+	ctx context.Context,
+	tableName string,
+	table *model.Table,
+	c *expression.ConditionBuilder,
+	returnOld bool,
+	head []*dynamoDBValue,
+	r deleteItemReader,
+) (map[string]interface{}, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	var expr *expression.Expression
+	if c != nil {
+		e, err := expression.NewBuilder().WithCondition(*c).Build()
+		if err != nil {
+			return nil, err
+		}
+		expr = &e
+	}
+
+	deleted := 0
+	failed := make([]map[string]interface{}, 0)
+	old := make([]map[string]types.AttributeValue, 0)
+	del := func(v *dynamoDBValue) error {
+		key, err := deleteKey(table, v)
+		if err != nil {
+			return err
+		}
+		input := &dynamodb.DeleteItemInput{
+			TableName: aws.String(tableName),
+			Key:       key,
+		}
+		if expr != nil {
+			input.ConditionExpression = expr.Condition()
+			input.ExpressionAttributeNames = expr.Names()
+			input.ExpressionAttributeValues = expressionValues(*expr)
+		}
+		if returnOld {
+			input.ReturnValues = types.ReturnValueAllOld
+		}
+		res, err := cli.DeleteItem(ctx, input)
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			var k map[string]interface{}
+			if err := attributevalue.UnmarshalMap(key, &k); err != nil {
+				return err
 			}
+			failed = append(failed, k)
+			return nil
 		}
-		return deleteItems, nil
-	case map[string]interface{}:
-		deleteItems := make([]*dynamoDBValue, 1)
-		deleteItems[0], err = getValueFromRequestItems(j)
 		if err != nil {
+			return fmt.Errorf("%v (%d items have been deleted)", err, deleted)
+		}
+		deleted++
+		if returnOld && len(res.Attributes) != 0 {
+			old = append(old, res.Attributes)
+		}
+		return nil
+	}
+
+	for i := range head {
+		if err := del(head[i]); err != nil {
 			return nil, err
 		}
-		if len(deleteItems[0].partitionValue) == 0 {
-			return nil, fmt.Errorf("required partition value: %v", jsonItem)
+	}
+	for {
+		v, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v (%d items have been deleted)", err, deleted)
+		}
+		if err := del(v); err != nil {
+			return nil, err
 		}
+	}
 
-		return deleteItems, nil
-	default:
-		return nil, fmt.Errorf("unknown error: %v", jsonItem)
+	res := map[string]interface{}{
+		"unprocessed":            []string{},
+		"conditionalCheckFailed": failed,
 	}
+	if returnOld {
+		var items []map[string]interface{}
+		if err := attributevalue.UnmarshalListOfMaps(old, &items); err != nil {
+			return nil, err
+		}
+		res["deleted"] = items
+	}
+	return res, nil
 }
 
 func getValueFromRequestItems(jsonItem map[string]interface{}) (*dynamoDBValue, error) {
@@ -115,10 +326,13 @@ func getValueFromRequestItems(jsonItem map[string]interface{}) (*dynamoDBValue,
 	for k := range jsonItem {
 		var v string
 		// Primary key is allowed string, number, byte.
-		if reflect.TypeOf(jsonItem[k]).Kind() == reflect.Float64 {
-			v = strconv.FormatFloat(jsonItem[k].(float64), 'f', -1, 64)
-		} else {
-			v = jsonItem[k].(string)
+		switch t := jsonItem[k].(type) {
+		case float64, json.Number:
+			v = numberString(t)
+		case string:
+			v = t
+		default:
+			return nil, fmt.Errorf("invalid key value specified: %v", t)
 		}
 		switch {
 		case k == "partition":
@@ -142,35 +356,112 @@ func (i *Instance) Delete(
 	partitionValue,
 	sortValue,
 	fileName string,
-	f func(string) (string, error),
+	f func(string) (io.ReadCloser, error),
+	option DeleteOption,
 ) error {
-	var items []*dynamoDBValue
+	format, err := convertToInputFormat(option.InputFormat)
+	if err != nil {
+		return err
+	}
+	var c *expression.ConditionBuilder
+	if len(option.Condition) != 0 {
+		c, err = analyseFilterCondition(option.Condition)
+		if err != nil {
+			return err
+		}
+	}
+	search := len(option.Where) != 0 || option.FromQuery
+	var r deleteItemReader
 	switch {
+	case search && len(fileName) != 0:
+		return fmt.Errorf("use either --where, --from-query or --input-file option")
+	case option.FromQuery && len(partitionValue) == 0:
+		return fmt.Errorf("required --partition option with --from-query")
+	case len(option.Where) != 0 && !option.FromQuery && len(partitionValue) != 0:
+		return fmt.Errorf("use --from-query to delete the items by --partition and --where")
+	case search:
 	case len(partitionValue) == 0 && len(fileName) == 0:
 		return fmt.Errorf("required either --partition or --input-file option")
 	case len(partitionValue) != 0 && len(fileName) != 0:
 		return fmt.Errorf("use either --partition or --input-file option")
 	case len(fileName) != 0:
-		var err error
-		strJSONItems, err := f(fileName)
+		in, err := f(fileName)
 		if err != nil {
 			return err
 		}
-		items, err = analyseDeleteRequestItem(strJSONItems)
+		defer in.Close()
+		r, err = newDeleteItemReader(in, fileName, format)
 		if err != nil {
 			return err
 		}
 	case len(partitionValue) != 0:
-		items = append(items, &dynamoDBValue{
-			partitionValue: partitionValue,
-			sortValue:      sortValue,
-		})
+		r = &sliceDeleteItemReader{
+			items: []*dynamoDBValue{
+				{
+					partitionValue: partitionValue,
+					sortValue:      sortValue,
+				},
+			},
+		}
+	}
+	var head *dynamoDBValue
+	if r != nil {
+		// Check the head of input before connecting.
+		head, err = r.next()
+		if err == io.EOF {
+			return fmt.Errorf("no item in the input")
+		}
+		if err != nil {
+			return err
+		}
 	}
 
-	cli := i.NewClient.CreateInstance()
+	cli := i.createInstance(w)
 	ctx = context.WithValue(ctx, newClientKey, cli)
 
-	res, err := deleteItems(ctx, tableName, items)
+	table, err := describeTable(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	var res map[string]interface{}
+	var heads []*dynamoDBValue
+	if search {
+		keys, err := findDeleteKeys(ctx, table, tableName, partitionValue, sortValue, option.Where, option.FromQuery)
+		if err != nil {
+			return err
+		}
+		if len(keys) != 0 && !i.DryRun {
+			if err := option.confirm(tableName, keys, true); err != nil {
+				return err
+			}
+		}
+		items := make([]*dynamoDBValue, len(keys))
+		for i := range keys {
+			items[i] = &dynamoDBValue{item: keys[i]}
+		}
+		r = &sliceDeleteItemReader{items: items}
+	} else {
+		heads = append(heads, head)
+		if !i.DryRun {
+			var keys []map[string]types.AttributeValue
+			if option.needsPrompt() {
+				var items []*dynamoDBValue
+				items, keys, err = readDeleteKeys(table, heads, r)
+				if err != nil {
+					return err
+				}
+				heads, r = nil, &sliceDeleteItemReader{items: items}
+			}
+			if err := option.confirm(tableName, keys, false); err != nil {
+				return err
+			}
+		}
+	}
+	if c != nil || option.ReturnOld {
+		res, err = conditionalDeleteItems(ctx, tableName, table, c, option.ReturnOld, heads, r)
+	} else {
+		res, err = deleteItems(ctx, tableName, table, heads, r)
+	}
 	if err != nil {
 		return err
 	}
@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/hirano00o/edy/mocks"
 )
@@ -23,6 +26,7 @@ func TestInstance_Delete(t *testing.T) {
 		sortValue      string
 		fileName       string
 		f              func(string) (string, error)
+		option         DeleteOption
 	}
 	tests := []struct {
 		name    string
@@ -32,7 +36,7 @@ func TestInstance_Delete(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "Delete with partition value",
+			name: "Delete with partition value without sort value of the table which has sort key",
 			args: args{
 				ctx:            context.Background(),
 				tableName:      "TEST",
@@ -41,6 +45,52 @@ func TestInstance_Delete(t *testing.T) {
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
 
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "Delete from file without sort value of the table which has sort key",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				fileName:  "TEST.json",
+				f: func(s string) (string, error) {
+					return "[{\"partition\":\"TEST_VALUE1\",\"sort\":\"TEST_VALUE2\"},{\"partition\":\"TEST_VALUE3\"}]", nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "Delete with partition and sort value",
+			args: args{
+				ctx:            context.Background(),
+				tableName:      "TEST",
+				partitionValue: "TEST_VALUE1",
+				sortValue:      "TEST_VALUE2",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
@@ -57,6 +107,9 @@ func TestInstance_Delete(t *testing.T) {
 										"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{
 											Value: "TEST_VALUE1",
 										},
+										"TEST_SORT_ATTRIBUTE": &types.AttributeValueMemberS{
+											Value: "TEST_VALUE2",
+										},
 									},
 								},
 							},
@@ -72,12 +125,14 @@ func TestInstance_Delete(t *testing.T) {
 			wantW: "{\n  \"unprocessed\": []\n}\n",
 		},
 		{
-			name: "Delete with partition and sort value",
+			name: "Delete from file",
 			args: args{
-				ctx:            context.Background(),
-				tableName:      "TEST",
-				partitionValue: "TEST_VALUE1",
-				sortValue:      "TEST_VALUE2",
+				ctx:       context.Background(),
+				tableName: "TEST",
+				fileName:  "TEST.json",
+				f: func(s string) (string, error) {
+					return "{\"partition\":\"TEST_VALUE1\",\"sort\":\"TEST_VALUE2\"}", nil
+				},
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
@@ -116,13 +171,14 @@ func TestInstance_Delete(t *testing.T) {
 			wantW: "{\n  \"unprocessed\": []\n}\n",
 		},
 		{
-			name: "Delete from file",
+			name: "Delete from DynamoDB JSON file",
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
 				fileName:  "TEST.json",
 				f: func(s string) (string, error) {
-					return "{\"partition\":\"TEST_VALUE1\",\"sort\":\"TEST_VALUE2\"}", nil
+					return "{\"Items\":[{\"TEST_PARTITION_ATTRIBUTE\":{\"S\":\"TEST_VALUE1\"}," +
+						"\"TEST_SORT_ATTRIBUTE\":{\"S\":\"TEST_VALUE2\"},\"TEST_ATTRIBUTE_1\":{\"N\":\"1\"}}],\"Count\":1}", nil
 				},
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
@@ -161,6 +217,62 @@ func TestInstance_Delete(t *testing.T) {
 			},
 			wantW: "{\n  \"unprocessed\": []\n}\n",
 		},
+		{
+			name: "Error partition key is missing in DynamoDB JSON file",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				fileName:  "TEST.json",
+				f: func(s string) (string, error) {
+					return "[{\"TEST_SORT_ATTRIBUTE\":{\"S\":\"TEST_VALUE2\"}}]", nil
+				},
+				option: DeleteOption{
+					InputFormat: "dynamodb-json",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				table := describeTableOutputFixture(t, false)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(table, nil)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error sort key is missing in DynamoDB JSON file",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				fileName:  "TEST.json",
+				f: func(s string) (string, error) {
+					return "[{\"TEST_PARTITION_ATTRIBUTE\":{\"S\":\"TEST_VALUE1\"}}]", nil
+				},
+				option: DeleteOption{
+					InputFormat: "dynamodb-json",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				table := describeTableOutputFixture(t, false)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(table, nil)
+
+				return m
+			},
+			wantErr: true,
+		},
 		{
 			name: "Multiple delete from file",
 			args: args{
@@ -499,6 +611,341 @@ func TestInstance_Delete(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Delete items where the condition matches",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option: DeleteOption{
+					Where:        "TEST_ATTRIBUTE_2,N = 1",
+					Confirmation: Confirmation{Yes: true},
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+				expr, err := expression.NewBuilder().
+					WithCondition(expression.Equal(expression.Name("TEST_ATTRIBUTE_2"), expression.Value(1))).
+					WithProjection(expression.NamesList(
+						expression.Name("TEST_PARTITION_ATTRIBUTE"),
+						expression.Name("TEST_SORT_ATTRIBUTE"),
+					)).
+					Build()
+				if err != nil {
+					t.Fatalf("expression build error: %v", err)
+				}
+				m.ScanAPIClient.On("Scan", ctx, &dynamodb.ScanInput{
+					TableName:                 aws.String("TEST"),
+					ExpressionAttributeNames:  expr.Names(),
+					ExpressionAttributeValues: expr.Values(),
+					FilterExpression:          expr.Condition(),
+					ProjectionExpression:      expr.Projection(),
+				}).Return(&dynamodb.ScanOutput{
+					Items: []map[string]types.AttributeValue{
+						{
+							"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "TEST_VALUE1"},
+							"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "TEST_VALUE2"},
+						},
+					},
+				}, nil)
+				input := &dynamodb.BatchWriteItemInput{
+					RequestItems: map[string][]types.WriteRequest{
+						"TEST": {
+							{
+								DeleteRequest: &types.DeleteRequest{
+									Key: map[string]types.AttributeValue{
+										"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "TEST_VALUE1"},
+										"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "TEST_VALUE2"},
+									},
+								},
+							},
+						},
+					},
+				}
+				m.BatchWriteItemClient.On("BatchWriteItem", ctx, input).Return(&dynamodb.BatchWriteItemOutput{
+					UnprocessedItems: map[string][]types.WriteRequest{},
+				}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"unprocessed\": []\n}\n",
+		},
+		{
+			name: "Delete items found by query after confirmation",
+			args: args{
+				ctx:            context.Background(),
+				tableName:      "TEST",
+				partitionValue: "TEST_VALUE1",
+				sortValue:      "< TEST_VALUE3",
+				option: DeleteOption{
+					FromQuery: true,
+					Confirmation: Confirmation{
+						Interactive: true,
+						Prompt: func(message string) (string, error) {
+							return "y\n", nil
+						},
+					},
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+				expr, err := expression.NewBuilder().
+					WithKeyCondition(expression.KeyEqual(
+						expression.Key("TEST_PARTITION_ATTRIBUTE"),
+						expression.Value("TEST_VALUE1"),
+					).And(expression.KeyLessThan(
+						expression.Key("TEST_SORT_ATTRIBUTE"),
+						expression.Value("TEST_VALUE3"),
+					))).
+					WithProjection(expression.NamesList(
+						expression.Name("TEST_PARTITION_ATTRIBUTE"),
+						expression.Name("TEST_SORT_ATTRIBUTE"),
+					)).
+					Build()
+				if err != nil {
+					t.Fatalf("expression build error: %v", err)
+				}
+				m.QueryAPIClient.On("Query", ctx, &dynamodb.QueryInput{
+					TableName:                 aws.String("TEST"),
+					ExpressionAttributeNames:  expr.Names(),
+					ExpressionAttributeValues: expr.Values(),
+					KeyConditionExpression:    expr.KeyCondition(),
+					ProjectionExpression:      expr.Projection(),
+				}).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{
+						{
+							"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "TEST_VALUE1"},
+							"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "TEST_VALUE2"},
+						},
+					},
+				}, nil)
+				input := &dynamodb.BatchWriteItemInput{
+					RequestItems: map[string][]types.WriteRequest{
+						"TEST": {
+							{
+								DeleteRequest: &types.DeleteRequest{
+									Key: map[string]types.AttributeValue{
+										"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "TEST_VALUE1"},
+										"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "TEST_VALUE2"},
+									},
+								},
+							},
+						},
+					},
+				}
+				m.BatchWriteItemClient.On("BatchWriteItem", ctx, input).Return(&dynamodb.BatchWriteItemOutput{
+					UnprocessedItems: map[string][]types.WriteRequest{},
+				}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"unprocessed\": []\n}\n",
+		},
+		{
+			name: "Delete is canceled",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option: DeleteOption{
+					Where: "TEST_ATTRIBUTE_2,N = 1",
+					Confirmation: Confirmation{
+						Interactive: true,
+						Prompt: func(message string) (string, error) {
+							return "n\n", nil
+						},
+					},
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+				m.ScanAPIClient.On("Scan", ctx, mock.Anything).Return(&dynamodb.ScanOutput{
+					Items: []map[string]types.AttributeValue{
+						{
+							"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "TEST_VALUE1"},
+							"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "TEST_VALUE2"},
+						},
+					},
+				}, nil)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "Delete with condition and return old items",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				fileName:  "TEST.json",
+				f: func(s string) (string, error) {
+					return "[{\"partition\":\"TEST_VALUE1\",\"sort\":\"TEST_VALUE2\"}," +
+						"{\"partition\":\"TEST_VALUE3\",\"sort\":\"TEST_VALUE4\"}]", nil
+				},
+				option: DeleteOption{
+					Condition: "TEST_ATTRIBUTE_1,S = archived",
+					ReturnOld: true,
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+				expr, err := expression.NewBuilder().
+					WithCondition(expression.Equal(expression.Name("TEST_ATTRIBUTE_1"), expression.Value("archived"))).
+					Build()
+				if err != nil {
+					t.Fatalf("expression build error: %v", err)
+				}
+				m.DeleteItemClient.On("DeleteItem", ctx, &dynamodb.DeleteItemInput{
+					TableName: aws.String("TEST"),
+					Key: map[string]types.AttributeValue{
+						"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "TEST_VALUE1"},
+						"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "TEST_VALUE2"},
+					},
+					ConditionExpression:       expr.Condition(),
+					ExpressionAttributeNames:  expr.Names(),
+					ExpressionAttributeValues: expr.Values(),
+					ReturnValues:              types.ReturnValueAllOld,
+				}).Return(&dynamodb.DeleteItemOutput{
+					Attributes: map[string]types.AttributeValue{
+						"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "TEST_VALUE1"},
+						"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "TEST_VALUE2"},
+						"TEST_ATTRIBUTE_1":         &types.AttributeValueMemberS{Value: "archived"},
+					},
+				}, nil)
+				m.DeleteItemClient.On("DeleteItem", ctx, &dynamodb.DeleteItemInput{
+					TableName: aws.String("TEST"),
+					Key: map[string]types.AttributeValue{
+						"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "TEST_VALUE3"},
+						"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "TEST_VALUE4"},
+					},
+					ConditionExpression:       expr.Condition(),
+					ExpressionAttributeNames:  expr.Names(),
+					ExpressionAttributeValues: expr.Values(),
+					ReturnValues:              types.ReturnValueAllOld,
+				}).Return(nil, &types.ConditionalCheckFailedException{})
+
+				return m
+			},
+			wantW: jsonFixture(t, map[string]interface{}{
+				"conditionalCheckFailed": []map[string]interface{}{
+					{
+						"TEST_PARTITION_ATTRIBUTE": "TEST_VALUE3",
+						"TEST_SORT_ATTRIBUTE":      "TEST_VALUE4",
+					},
+				},
+				"deleted": []map[string]interface{}{
+					{
+						"TEST_PARTITION_ATTRIBUTE": "TEST_VALUE1",
+						"TEST_SORT_ATTRIBUTE":      "TEST_VALUE2",
+						"TEST_ATTRIBUTE_1":         "archived",
+					},
+				},
+				"unprocessed": []string{},
+			}),
+		},
+		{
+			name: "Error invalid condition",
+			args: args{
+				ctx:            context.Background(),
+				tableName:      "TEST",
+				partitionValue: "TEST_VALUE1",
+				option:         DeleteOption{Condition: "TEST_ATTRIBUTE_1 = archived"},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Delete with partition value is canceled",
+			args: args{
+				ctx:            context.Background(),
+				tableName:      "TEST",
+				partitionValue: "TEST_VALUE1",
+				sortValue:      "TEST_VALUE2",
+				option: DeleteOption{
+					Confirmation: Confirmation{
+						Interactive: true,
+						Prompt: func(message string) (string, error) {
+							if !strings.HasPrefix(message, "1 items will be deleted from TEST.") {
+								return "", fmt.Errorf("unexpected message: %s", message)
+							}
+							return "no\n", nil
+						},
+					},
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error both where and file is not empty",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				fileName:  "TEST.json",
+				f: func(s string) (string, error) {
+					return "", nil
+				},
+				option: DeleteOption{Where: "TEST_ATTRIBUTE_2,N = 1"},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error from query without partition value",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option:    DeleteOption{FromQuery: true},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -514,7 +961,8 @@ func TestInstance_Delete(t *testing.T) {
 				tt.args.partitionValue,
 				tt.args.sortValue,
 				tt.args.fileName,
-				tt.args.f,
+				fileFixture(t, tt.args.f),
+				tt.args.option,
 			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
@@ -527,7 +975,7 @@ func TestInstance_Delete(t *testing.T) {
 	}
 }
 
-func Test_analyseDeleteRequestItem(t *testing.T) {
+func Test_newDeleteItemReader(t *testing.T) {
 	type args struct {
 		requestJSONStr string
 	}
@@ -606,14 +1054,32 @@ func Test_analyseDeleteRequestItem(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := analyseDeleteRequestItem(tt.args.requestJSONStr)
+			got, err := readDeleteItems(tt.args.requestJSONStr)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("analyseDeleteRequestItem() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("newDeleteItemReader() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("analyseDeleteRequestItem() got = %v, want %v", got, tt.want)
+				t.Errorf("newDeleteItemReader() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func readDeleteItems(requestJSONStr string) ([]*dynamoDBValue, error) {
+	r, err := newDeleteItemReader(strings.NewReader(requestJSONStr), "", autoInputType)
+	if err != nil {
+		return nil, err
+	}
+	var items []*dynamoDBValue
+	for {
+		v, err := r.next()
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+}
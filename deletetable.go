@@ -0,0 +1,160 @@
+package edy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/client"
+	"github.com/hirano00o/edy/model"
+)
+
+func progressf(w io.Writer, format string, a ...interface{}) {
+	if w != nil {
+		fmt.Fprintf(w, format, a...)
+	}
+}
+
+func deleteTable(ctx context.Context, tableName string, wait bool) error {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	_, err := cli.DeleteTable(ctx, &dynamodb.DeleteTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return err
+	}
+	if wait {
+		return waitTableDeleted(ctx, tableName)
+	}
+	return nil
+}
+
+// truncateItems deletes all items of the table, which are found by scan with the key only projection.
+func truncateItems(ctx context.Context, table *model.Table, progress io.Writer) (map[string]interface{}, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	names := []string{table.PartitionKey.Name}
+	if table.SortKey != nil {
+		names = append(names, table.SortKey.Name)
+	}
+	input, err := scanInput(table.Name, "", strings.Join(names, ","))
+	if err != nil {
+		return nil, err
+	}
+	bw := newBatchWriter(ctx, table.Name)
+	paginator := dynamodb.NewScanPaginator(cli, input)
+	for paginator.HasMorePages() {
+		res, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%v (%d items have been deleted)", err, bw.written)
+		}
+		for i := range res.Items {
+			err := bw.add(types.WriteRequest{
+				DeleteRequest: &types.DeleteRequest{Key: res.Items[i]},
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		progressf(progress, "%d items have been deleted from %s\n", bw.written, table.Name)
+	}
+	unprocessed, err := bw.close()
+	if err != nil {
+		return nil, err
+	}
+
+	res := unprocessedResult(unprocessed)
+	res["deleted"] = bw.written - len(unprocessed)
+	return res, nil
+}
+
+// recreateTable deletes the table and creates it from the described schema.
+func recreateTable(ctx context.Context, table *model.Table, wait bool, progress io.Writer) error {
+	progressf(progress, "Deleting %s\n", table.Name)
+	if err := deleteTable(ctx, table.Name, wait); err != nil {
+		return err
+	}
+	progressf(progress, "Creating %s\n", table.Name)
+	return createTable(ctx, table, wait)
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", strings.Repeat(" ", 2))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s\n", string(b))
+	return nil
+}
+
+// DeleteTable deletes the table and waits until it is deleted.
+// The schema of the deleted table is shown, which can be used by CreateTable to restore the table.
+func (i *Instance) DeleteTable(ctx context.Context, w io.Writer, tableName string, option DeleteTableOption) error {
+	cli := i.createInstance(w)
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	t, err := describeTableDetail(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	if !i.DryRun {
+		if err := option.confirmTable(tableName, "deleted"); err != nil {
+			return err
+		}
+	}
+	if err := deleteTable(ctx, tableName, !i.DryRun); err != nil {
+		return err
+	}
+	if i.DryRun {
+		return nil
+	}
+	return printJSON(w, t)
+}
+
+// Truncate deletes all items of the table.
+func (i *Instance) Truncate(ctx context.Context, w io.Writer, tableName string, option TruncateOption) error {
+	cli := i.createInstance(w)
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	// Recreating needs the settings such as TTL in addition to the schema.
+	describe := describeTable
+	if option.Recreate {
+		describe = describeTableDetail
+	}
+	t, err := describe(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	if !i.DryRun {
+		if err := option.confirmTable(tableName, "truncated"); err != nil {
+			return err
+		}
+	}
+
+	if !option.Recreate {
+		res, err := truncateItems(ctx, t, option.Progress)
+		if err != nil {
+			return err
+		}
+		return printJSON(w, res)
+	}
+
+	if err := recreateTable(ctx, t, !i.DryRun, option.Progress); err != nil {
+		return err
+	}
+	if i.DryRun {
+		return nil
+	}
+	t, err = describe(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	return printJSON(w, t)
+}
@@ -0,0 +1,291 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/mocks"
+	"github.com/hirano00o/edy/model"
+)
+
+func TestInstance_DeleteTable(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		tableName string
+		option    DeleteTableOption
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mocking func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI
+		wantW   string
+		wantErr bool
+	}{
+		{
+			name: "DeleteTable",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option:    DeleteTableOption{Confirmation: Confirmation{Yes: true}},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				input := &dynamodb.DescribeTableInput{TableName: aws.String("TEST")}
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, input).
+					Return(activeDescribeTableOutputFixture(t), nil).Once()
+				describeTableDetailMock(t, ctx, m)
+				m.DeleteTableClient.On("DeleteTable", ctx, &dynamodb.DeleteTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(&dynamodb.DeleteTableOutput{}, nil)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, input).
+					Return(nil, &types.ResourceNotFoundException{}).Once()
+
+				return m
+			},
+			wantW: jsonFixture(t, model.Table{
+				Arn:    "TEST_ARN",
+				Name:   "TEST",
+				Status: "ACTIVE",
+				PartitionKey: &model.Key{
+					Name:    "TEST_PARTITION_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				SortKey: &model.Key{
+					Name:    "TEST_SORT_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				TTL:                 &model.TTL{Status: "DISABLED"},
+				PointInTimeRecovery: &model.PointInTimeRecovery{Status: "DISABLED"},
+				ItemCount:           1,
+			}),
+		},
+		{
+			name: "DeleteTable requires confirmation",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+				describeTableDetailMock(t, ctx, m)
+
+				return m
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: m,
+			}
+			w := &bytes.Buffer{}
+			err := i.DeleteTable(tt.args.ctx, w, tt.args.tableName, tt.args.option)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteTable() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("DeleteTable() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			m.DeleteTableClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInstance_Truncate(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		tableName string
+		option    TruncateOption
+	}
+	tests := []struct {
+		name         string
+		args         args
+		mocking      func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI
+		wantW        string
+		wantProgress string
+		wantErr      bool
+	}{
+		{
+			name: "Truncate by deleting items",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option:    TruncateOption{Confirmation: Confirmation{Yes: true}},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+				expr, err := expression.NewBuilder().WithProjection(expression.NamesList(
+					expression.Name("TEST_PARTITION_ATTRIBUTE"),
+					expression.Name("TEST_SORT_ATTRIBUTE"),
+				)).Build()
+				if err != nil {
+					t.Fatalf("expression build error: %v", err)
+				}
+				keys := []map[string]types.AttributeValue{
+					{
+						"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "P1"},
+						"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S1"},
+					},
+					{
+						"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "P2"},
+						"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S2"},
+					},
+				}
+				m.ScanAPIClient.On("Scan", ctx, &dynamodb.ScanInput{
+					TableName:                aws.String("TEST"),
+					ProjectionExpression:     expr.Projection(),
+					ExpressionAttributeNames: expr.Names(),
+				}).Return(&dynamodb.ScanOutput{Items: keys, Count: 2}, nil)
+				m.BatchWriteItemClient.On("BatchWriteItem", ctx, &dynamodb.BatchWriteItemInput{
+					RequestItems: map[string][]types.WriteRequest{
+						"TEST": {
+							{DeleteRequest: &types.DeleteRequest{Key: keys[0]}},
+							{DeleteRequest: &types.DeleteRequest{Key: keys[1]}},
+						},
+					},
+				}).Return(&dynamodb.BatchWriteItemOutput{
+					UnprocessedItems: map[string][]types.WriteRequest{},
+				}, nil)
+
+				return m
+			},
+			wantW:        "{\n  \"deleted\": 2,\n  \"unprocessed\": []\n}\n",
+			wantProgress: "0 items have been deleted from TEST\n",
+		},
+		{
+			name: "Truncate by recreating table",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option: TruncateOption{
+					Confirmation: Confirmation{Yes: true},
+					Recreate:     true,
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				input := &dynamodb.DescribeTableInput{TableName: aws.String("TEST")}
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, input).
+					Return(activeDescribeTableOutputFixture(t), nil).Once()
+				describeTableDetailMock(t, ctx, m)
+				m.DeleteTableClient.On("DeleteTable", ctx, &dynamodb.DeleteTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(&dynamodb.DeleteTableOutput{}, nil)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, input).
+					Return(nil, &types.ResourceNotFoundException{}).Once()
+				m.CreateTableClient.On("CreateTable", ctx, &dynamodb.CreateTableInput{
+					TableName: aws.String("TEST"),
+					AttributeDefinitions: []types.AttributeDefinition{
+						{AttributeName: aws.String("TEST_PARTITION_ATTRIBUTE"), AttributeType: types.ScalarAttributeTypeS},
+						{AttributeName: aws.String("TEST_SORT_ATTRIBUTE"), AttributeType: types.ScalarAttributeTypeS},
+					},
+					KeySchema: []types.KeySchemaElement{
+						{AttributeName: aws.String("TEST_PARTITION_ATTRIBUTE"), KeyType: types.KeyTypeHash},
+						{AttributeName: aws.String("TEST_SORT_ATTRIBUTE"), KeyType: types.KeyTypeRange},
+					},
+					BillingMode: types.BillingModePayPerRequest,
+				}).Return(&dynamodb.CreateTableOutput{}, nil)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, input).
+					Return(activeDescribeTableOutputFixture(t), nil)
+
+				return m
+			},
+			wantW: jsonFixture(t, model.Table{
+				Arn:    "TEST_ARN",
+				Name:   "TEST",
+				Status: "ACTIVE",
+				PartitionKey: &model.Key{
+					Name:    "TEST_PARTITION_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				SortKey: &model.Key{
+					Name:    "TEST_SORT_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				TTL:                 &model.TTL{Status: "DISABLED"},
+				PointInTimeRecovery: &model.PointInTimeRecovery{Status: "DISABLED"},
+				ItemCount:           1,
+			}),
+			wantProgress: "Deleting TEST\nCreating TEST\n",
+		},
+		{
+			name: "Truncate is canceled",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option: TruncateOption{Confirmation: Confirmation{
+					Interactive: true,
+					Prompt: func(string) (string, error) {
+						return "n\n", nil
+					},
+				}},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+
+				return m
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: m,
+			}
+			w := &bytes.Buffer{}
+			progress := &bytes.Buffer{}
+			tt.args.option.Progress = progress
+			err := i.Truncate(tt.args.ctx, w, tt.args.tableName, tt.args.option)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Truncate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("Truncate() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			if gotProgress := progress.String(); gotProgress != tt.wantProgress {
+				t.Errorf("Truncate() gotProgress = %v, want %v", gotProgress, tt.wantProgress)
+			}
+		})
+	}
+}
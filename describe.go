@@ -1,14 +1,18 @@
 package edy
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/hirano00o/edy/client"
 	"github.com/hirano00o/edy/model"
@@ -23,9 +27,12 @@ func describeTable(ctx context.Context, tableName string) (*model.Table, error)
 		return nil, err
 	}
 	t := model.Table{
-		Arn:       aws.ToString(res.Table.TableArn),
-		Name:      aws.ToString(res.Table.TableName),
-		ItemCount: res.Table.ItemCount,
+		Arn:              aws.ToString(res.Table.TableArn),
+		Name:             aws.ToString(res.Table.TableName),
+		Status:           string(res.Table.TableStatus),
+		CreationDateTime: res.Table.CreationDateTime,
+		ItemCount:        res.Table.ItemCount,
+		SizeBytes:        res.Table.TableSizeBytes,
 	}
 	attr := make(map[string]model.AttributeType)
 	for _, a := range res.Table.AttributeDefinitions {
@@ -65,25 +72,258 @@ func describeTable(ctx context.Context, tableName string) (*model.Table, error)
 				t.GSI[i].SortKey.TypeStr = attr[t.GSI[i].SortKey.Name].String()
 			}
 		}
+		t.GSI[i].Projection = projectionOf(g.Projection)
+		t.GSI[i].ProvisionedThroughput = provisionedThroughputOf(g.ProvisionedThroughput)
+		t.GSI[i].Status = string(g.IndexStatus)
+	}
+	for _, l := range res.Table.LocalSecondaryIndexes {
+		lsi := &model.LocalSecondaryIndex{
+			Name:       aws.ToString(l.IndexName),
+			Projection: projectionOf(l.Projection),
+		}
+		for j := range l.KeySchema {
+			if l.KeySchema[j].KeyType == "RANGE" {
+				name := aws.ToString(l.KeySchema[j].AttributeName)
+				lsi.SortKey = &model.Key{Name: name, Type: attr[name], TypeStr: attr[name].String()}
+			}
+		}
+		t.LSI = append(t.LSI, lsi)
+	}
+	t.ProvisionedThroughput = provisionedThroughputOf(res.Table.ProvisionedThroughput)
+	// The billing mode summary is not returned for the table which has always been provisioned.
+	if res.Table.BillingModeSummary != nil {
+		t.BillingMode = string(res.Table.BillingModeSummary.BillingMode)
+	} else if t.ProvisionedThroughput != nil {
+		t.BillingMode = string(types.BillingModeProvisioned)
+	}
+	if s := res.Table.TableClassSummary; s != nil {
+		t.TableClass = string(s.TableClass)
+	}
+	if s := res.Table.StreamSpecification; s != nil && aws.ToBool(s.StreamEnabled) {
+		t.Stream = &model.Stream{ViewType: string(s.StreamViewType)}
+	}
+	if s := res.Table.SSEDescription; s != nil {
+		t.SSE = &model.SSE{
+			Status:          string(s.Status),
+			Type:            string(s.SSEType),
+			KMSMasterKeyArn: aws.ToString(s.KMSMasterKeyArn),
+		}
 	}
 
 	return &t, nil
 }
 
-func (i *Instance) DescribeTable(ctx context.Context, w io.Writer, tableName string) error {
-	cli := i.NewClient.CreateInstance()
-	ctx = context.WithValue(ctx, newClientKey, cli)
+// describeTableDetail describes the table with the settings which DescribeTable does not return,
+// such as TTL, point-in-time recovery and tags. The setting is omitted if it cannot be described,
+// because the role may not be allowed to, and DynamoDB Local lacks some of the APIs.
+func describeTableDetail(ctx context.Context, tableName string) (*model.Table, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
 
 	t, err := describeTable(ctx, tableName)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if ttl, err := describeTTL(ctx, tableName); err == nil {
+		t.TTL = ttl
+	}
+
+	backups, err := cli.DescribeContinuousBackups(ctx, &dynamodb.DescribeContinuousBackupsInput{
+		TableName: aws.String(tableName),
+	})
+	if err == nil && backups.ContinuousBackupsDescription != nil {
+		if d := backups.ContinuousBackupsDescription.PointInTimeRecoveryDescription; d != nil {
+			t.PointInTimeRecovery = &model.PointInTimeRecovery{Status: string(d.PointInTimeRecoveryStatus)}
+		}
 	}
 
-	b, err := json.MarshalIndent(t, "", strings.Repeat(" ", 2))
+	t.Tags = describeTags(ctx, t.Arn)
+
+	return t, nil
+}
+
+// describeTags returns the tags of the table, or nil if they cannot be listed.
+func describeTags(ctx context.Context, arn string) map[string]string {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	var tags map[string]string
+	input := &dynamodb.ListTagsOfResourceInput{ResourceArn: aws.String(arn)}
+	for {
+		res, err := cli.ListTagsOfResource(ctx, input)
+		if err != nil {
+			return nil
+		}
+		for _, tag := range res.Tags {
+			if tags == nil {
+				tags = make(map[string]string)
+			}
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		if res.NextToken == nil {
+			return tags
+		}
+		input.NextToken = res.NextToken
+	}
+}
+
+func projectionOf(p *types.Projection) *model.Projection {
+	if p == nil {
+		return nil
+	}
+	return &model.Projection{
+		Type:             string(p.ProjectionType),
+		NonKeyAttributes: p.NonKeyAttributes,
+	}
+}
+
+// provisionedThroughputOf returns nil for on-demand, whose capacity units are 0.
+func provisionedThroughputOf(p *types.ProvisionedThroughputDescription) *model.ProvisionedThroughput {
+	if p == nil || (aws.ToInt64(p.ReadCapacityUnits) == 0 && aws.ToInt64(p.WriteCapacityUnits) == 0) {
+		return nil
+	}
+	return &model.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.ToInt64(p.ReadCapacityUnits),
+		WriteCapacityUnits: aws.ToInt64(p.WriteCapacityUnits),
+	}
+}
+
+func keyText(k *model.Key) string {
+	if k == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%s)", k.Name, k.TypeStr)
+}
+
+func capacityText(p *model.ProvisionedThroughput) string {
+	if p == nil {
+		return "-"
+	}
+	return fmt.Sprintf("read %d, write %d", p.ReadCapacityUnits, p.WriteCapacityUnits)
+}
+
+func projectionText(p *model.Projection) string {
+	if p == nil {
+		return "-"
+	}
+	if len(p.NonKeyAttributes) != 0 {
+		return fmt.Sprintf("%s (%s)", p.Type, strings.Join(p.NonKeyAttributes, ", "))
+	}
+	return p.Type
+}
+
+func orHyphen(s string) string {
+	if len(s) == 0 {
+		return "-"
+	}
+	return s
+}
+
+// tableText formats the table for humans, which has the properties and the indexes in columns.
+func tableText(t *model.Table) (string, error) {
+	var b bytes.Buffer
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+
+	created := "-"
+	if t.CreationDateTime != nil {
+		created = t.CreationDateTime.Format(time.RFC3339)
+	}
+	stream, ttl, sse, pitr := "-", "-", "-", "-"
+	if t.Stream != nil {
+		stream = t.Stream.ViewType
+	}
+	if t.TTL != nil {
+		ttl = t.TTL.Status
+		if len(t.TTL.AttributeName) != 0 {
+			ttl = fmt.Sprintf("%s (%s)", t.TTL.AttributeName, t.TTL.Status)
+		}
+	}
+	if t.SSE != nil {
+		sse = t.SSE.Status
+		if len(t.SSE.Type) != 0 {
+			sse = fmt.Sprintf("%s (%s)", t.SSE.Status, t.SSE.Type)
+		}
+	}
+	if t.PointInTimeRecovery != nil {
+		pitr = t.PointInTimeRecovery.Status
+	}
+	tags := make([]string, 0, len(t.Tags))
+	for k, v := range t.Tags {
+		tags = append(tags, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(tags)
+
+	rows := [][2]string{
+		{"Table", t.Name},
+		{"ARN", t.Arn},
+		{"Status", orHyphen(t.Status)},
+		{"Created", created},
+		{"Partition key", keyText(t.PartitionKey)},
+		{"Sort key", keyText(t.SortKey)},
+		{"Billing mode", orHyphen(t.BillingMode)},
+		{"Capacity", capacityText(t.ProvisionedThroughput)},
+		{"Table class", orHyphen(t.TableClass)},
+		{"Item count", fmt.Sprint(t.ItemCount)},
+		{"Size", fmt.Sprintf("%d bytes", t.SizeBytes)},
+		{"Stream", stream},
+		{"TTL", ttl},
+		{"SSE", sse},
+		{"PITR", pitr},
+		{"Tags", orHyphen(strings.Join(tags, ", "))},
+	}
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s:\t%s\n", r[0], r[1])
+	}
+	if err := tw.Flush(); err != nil {
+		return "", err
+	}
+
+	if len(t.GSI) == 0 && len(t.LSI) == 0 {
+		return b.String(), nil
+	}
+	b.WriteString("\n")
+	tw = tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "INDEX\tTYPE\tPARTITION KEY\tSORT KEY\tPROJECTION\tSTATUS\tCAPACITY")
+	for _, g := range t.GSI {
+		fmt.Fprintf(tw, "%s\tGSI\t%s\t%s\t%s\t%s\t%s\n",
+			g.Name, keyText(g.PartitionKey), keyText(g.SortKey), projectionText(g.Projection),
+			orHyphen(g.Status), capacityText(g.ProvisionedThroughput))
+	}
+	for _, l := range t.LSI {
+		fmt.Fprintf(tw, "%s\tLSI\t%s\t%s\t%s\t-\t-\n",
+			l.Name, keyText(t.PartitionKey), keyText(l.SortKey), projectionText(l.Projection))
+	}
+	if err := tw.Flush(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// DescribeTable shows the table in JSON, YAML or table. JSON and YAML can be used as the spec of create-table.
+func (i *Instance) DescribeTable(ctx context.Context, w io.Writer, tableName, output string) error {
+	cli := i.NewClient.CreateInstance()
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	t, err := describeTableDetail(ctx, tableName)
 	if err != nil {
 		return err
 	}
-	fmt.Fprintf(w, "%s\n", string(b))
+
+	switch strings.ToLower(output) {
+	case "yaml":
+		str, err := marshalYAML(t)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s", str)
+	case "table":
+		str, err := tableText(t)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s", str)
+	default:
+		return printJSON(w, t)
+	}
 
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -18,6 +19,7 @@ func TestInstance_DescribeTable(t *testing.T) {
 	type args struct {
 		ctx       context.Context
 		tableName string
+		output    string
 	}
 	tests := []struct {
 		name    string
@@ -40,6 +42,7 @@ func TestInstance_DescribeTable(t *testing.T) {
 				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
 					TableName: aws.String("TEST"),
 				}).Return(describeTableOutputFixture(t, false), nil)
+				describeTableDetailMock(t, ctx, m)
 				return m
 			},
 			wantW: jsonFixture(t, model.Table{
@@ -53,7 +56,9 @@ func TestInstance_DescribeTable(t *testing.T) {
 					Name:    "TEST_SORT_ATTRIBUTE",
 					TypeStr: "S",
 				},
-				ItemCount: 1,
+				TTL:                 &model.TTL{Status: "DISABLED"},
+				PointInTimeRecovery: &model.PointInTimeRecovery{Status: "DISABLED"},
+				ItemCount:           1,
 			}),
 		},
 		{
@@ -70,6 +75,7 @@ func TestInstance_DescribeTable(t *testing.T) {
 				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
 					TableName: aws.String("TEST"),
 				}).Return(describeTableOutputFixture(t, true), nil)
+				describeTableDetailMock(t, ctx, m)
 				return m
 			},
 			wantW: jsonFixture(t, model.Table{
@@ -96,6 +102,147 @@ func TestInstance_DescribeTable(t *testing.T) {
 						},
 					},
 				},
+				TTL:                 &model.TTL{Status: "DISABLED"},
+				PointInTimeRecovery: &model.PointInTimeRecovery{Status: "DISABLED"},
+				ItemCount:           1,
+			}),
+		},
+		{
+			name: "Describe TEST table with all settings in YAML",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				output:    "yaml",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				describeTableFullMock(t, ctx, m)
+				return m
+			},
+			wantW: `tableArn: TEST_ARN
+tableName: TEST
+tableStatus: ACTIVE
+creationDateTime: "2021-06-01T12:00:00Z"
+partitionKey:
+  name: TEST_PARTITION_ATTRIBUTE
+  type: S
+sortKey:
+  name: TEST_SORT_ATTRIBUTE
+  type: S
+gsi:
+  - indexName: TEST_GSI
+    partitionKey:
+      name: TEST_ATTRIBUTE_1
+      type: S
+    sortKey:
+      name: TEST_ATTRIBUTE_2
+      type: N
+    projection:
+      type: INCLUDE
+      nonKeyAttributes:
+        - TEST_ATTRIBUTE_3
+    provisionedThroughput:
+      readCapacityUnits: 1
+      writeCapacityUnits: 1
+    indexStatus: ACTIVE
+billingMode: PROVISIONED
+tableClass: STANDARD_INFREQUENT_ACCESS
+provisionedThroughput:
+  readCapacityUnits: 5
+  writeCapacityUnits: 5
+stream:
+  viewType: NEW_AND_OLD_IMAGES
+ttl:
+  attributeName: ExpiresAt
+  status: ENABLED
+sse:
+  status: ENABLED
+  type: KMS
+  kmsMasterKeyArn: TEST_KMS_ARN
+pointInTimeRecovery:
+  status: ENABLED
+tags:
+  env: test
+  team: edy
+itemCount: 1
+tableSizeBytes: 2048
+`,
+		},
+		{
+			name: "Describe TEST table with all settings in table",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				output:    "table",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				describeTableFullMock(t, ctx, m)
+				return m
+			},
+			wantW: `Table:          TEST
+ARN:            TEST_ARN
+Status:         ACTIVE
+Created:        2021-06-01T12:00:00Z
+Partition key:  TEST_PARTITION_ATTRIBUTE (S)
+Sort key:       TEST_SORT_ATTRIBUTE (S)
+Billing mode:   PROVISIONED
+Capacity:       read 5, write 5
+Table class:    STANDARD_INFREQUENT_ACCESS
+Item count:     1
+Size:           2048 bytes
+Stream:         NEW_AND_OLD_IMAGES
+TTL:            ExpiresAt (ENABLED)
+SSE:            ENABLED (KMS)
+PITR:           ENABLED
+Tags:           env=test, team=edy
+
+INDEX     TYPE  PARTITION KEY         SORT KEY              PROJECTION                  STATUS  CAPACITY
+TEST_GSI  GSI   TEST_ATTRIBUTE_1 (S)  TEST_ATTRIBUTE_2 (N)  INCLUDE (TEST_ATTRIBUTE_3)  ACTIVE  read 1, write 1
+`,
+		},
+		{
+			name: "Describe TEST table without the settings which cannot be described",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+				m.DescribeTimeToLiveClient.On("DescribeTimeToLive", ctx, &dynamodb.DescribeTimeToLiveInput{
+					TableName: aws.String("TEST"),
+				}).Return(nil, fmt.Errorf("DescribeTimeToLive error"))
+				m.DescribeContinuousBackupsClient.On("DescribeContinuousBackups", ctx, &dynamodb.DescribeContinuousBackupsInput{
+					TableName: aws.String("TEST"),
+				}).Return(nil, fmt.Errorf("DescribeContinuousBackups error"))
+				m.ListTagsOfResourceClient.On("ListTagsOfResource", ctx, &dynamodb.ListTagsOfResourceInput{
+					ResourceArn: aws.String("TEST_ARN"),
+				}).Return(nil, fmt.Errorf("ListTagsOfResource error"))
+				return m
+			},
+			wantW: jsonFixture(t, model.Table{
+				Arn:  "TEST_ARN",
+				Name: "TEST",
+				PartitionKey: &model.Key{
+					Name:    "TEST_PARTITION_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				SortKey: &model.Key{
+					Name:    "TEST_SORT_ATTRIBUTE",
+					TypeStr: "S",
+				},
 				ItemCount: 1,
 			}),
 		},
@@ -125,7 +272,7 @@ func TestInstance_DescribeTable(t *testing.T) {
 				NewClient: mock,
 			}
 			w := &bytes.Buffer{}
-			err := i.DescribeTable(tt.args.ctx, w, tt.args.tableName)
+			err := i.DescribeTable(tt.args.ctx, w, tt.args.tableName, tt.args.output)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DescribeTable() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -137,6 +284,99 @@ func TestInstance_DescribeTable(t *testing.T) {
 	}
 }
 
+// describeTableDetailMock mocks the settings which DescribeTable does not return, which are disabled.
+func describeTableDetailMock(t *testing.T, ctx context.Context, m *mocks.MockDynamoDBAPI) {
+	t.Helper()
+
+	m.DescribeTimeToLiveClient.On("DescribeTimeToLive", ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String("TEST"),
+	}).Return(&dynamodb.DescribeTimeToLiveOutput{
+		TimeToLiveDescription: &types.TimeToLiveDescription{TimeToLiveStatus: types.TimeToLiveStatusDisabled},
+	}, nil)
+	m.DescribeContinuousBackupsClient.On("DescribeContinuousBackups", ctx, &dynamodb.DescribeContinuousBackupsInput{
+		TableName: aws.String("TEST"),
+	}).Return(&dynamodb.DescribeContinuousBackupsOutput{
+		ContinuousBackupsDescription: &types.ContinuousBackupsDescription{
+			ContinuousBackupsStatus: types.ContinuousBackupsStatusEnabled,
+			PointInTimeRecoveryDescription: &types.PointInTimeRecoveryDescription{
+				PointInTimeRecoveryStatus: types.PointInTimeRecoveryStatusDisabled,
+			},
+		},
+	}, nil)
+	m.ListTagsOfResourceClient.On("ListTagsOfResource", ctx, &dynamodb.ListTagsOfResourceInput{
+		ResourceArn: aws.String("TEST_ARN"),
+	}).Return(&dynamodb.ListTagsOfResourceOutput{}, nil)
+}
+
+// describeTableFullMock mocks the table which has all settings enabled.
+func describeTableFullMock(t *testing.T, ctx context.Context, m *mocks.MockDynamoDBAPI) {
+	t.Helper()
+
+	output := describeTableOutputFixture(t, true)
+	created := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	output.Table.TableStatus = types.TableStatusActive
+	output.Table.CreationDateTime = &created
+	output.Table.TableSizeBytes = 2048
+	output.Table.ProvisionedThroughput = &types.ProvisionedThroughputDescription{
+		ReadCapacityUnits:  aws.Int64(5),
+		WriteCapacityUnits: aws.Int64(5),
+	}
+	output.Table.GlobalSecondaryIndexes[0].IndexStatus = types.IndexStatusActive
+	output.Table.GlobalSecondaryIndexes[0].Projection = &types.Projection{
+		ProjectionType:   types.ProjectionTypeInclude,
+		NonKeyAttributes: []string{"TEST_ATTRIBUTE_3"},
+	}
+	output.Table.GlobalSecondaryIndexes[0].ProvisionedThroughput = &types.ProvisionedThroughputDescription{
+		ReadCapacityUnits:  aws.Int64(1),
+		WriteCapacityUnits: aws.Int64(1),
+	}
+	output.Table.TableClassSummary = &types.TableClassSummary{
+		TableClass: types.TableClassStandardInfrequentAccess,
+	}
+	output.Table.StreamSpecification = &types.StreamSpecification{
+		StreamEnabled:  aws.Bool(true),
+		StreamViewType: types.StreamViewTypeNewAndOldImages,
+	}
+	output.Table.SSEDescription = &types.SSEDescription{
+		Status:          types.SSEStatusEnabled,
+		SSEType:         types.SSETypeKms,
+		KMSMasterKeyArn: aws.String("TEST_KMS_ARN"),
+	}
+	m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String("TEST"),
+	}).Return(output, nil)
+	m.DescribeTimeToLiveClient.On("DescribeTimeToLive", ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String("TEST"),
+	}).Return(&dynamodb.DescribeTimeToLiveOutput{
+		TimeToLiveDescription: &types.TimeToLiveDescription{
+			AttributeName:    aws.String("ExpiresAt"),
+			TimeToLiveStatus: types.TimeToLiveStatusEnabled,
+		},
+	}, nil)
+	m.DescribeContinuousBackupsClient.On("DescribeContinuousBackups", ctx, &dynamodb.DescribeContinuousBackupsInput{
+		TableName: aws.String("TEST"),
+	}).Return(&dynamodb.DescribeContinuousBackupsOutput{
+		ContinuousBackupsDescription: &types.ContinuousBackupsDescription{
+			ContinuousBackupsStatus: types.ContinuousBackupsStatusEnabled,
+			PointInTimeRecoveryDescription: &types.PointInTimeRecoveryDescription{
+				PointInTimeRecoveryStatus: types.PointInTimeRecoveryStatusEnabled,
+			},
+		},
+	}, nil)
+	m.ListTagsOfResourceClient.On("ListTagsOfResource", ctx, &dynamodb.ListTagsOfResourceInput{
+		ResourceArn: aws.String("TEST_ARN"),
+	}).Return(&dynamodb.ListTagsOfResourceOutput{
+		Tags:      []types.Tag{{Key: aws.String("team"), Value: aws.String("edy")}},
+		NextToken: aws.String("TOKEN"),
+	}, nil)
+	m.ListTagsOfResourceClient.On("ListTagsOfResource", ctx, &dynamodb.ListTagsOfResourceInput{
+		ResourceArn: aws.String("TEST_ARN"),
+		NextToken:   aws.String("TOKEN"),
+	}).Return(&dynamodb.ListTagsOfResourceOutput{
+		Tags: []types.Tag{{Key: aws.String("env"), Value: aws.String("test")}},
+	}, nil)
+}
+
 func describeTableOutputFixture(t *testing.T, gsi bool) *dynamodb.DescribeTableOutput {
 	t.Helper()
 
@@ -0,0 +1,435 @@
+package edy
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/client"
+	"github.com/hirano00o/edy/model"
+)
+
+const diffUnifiedFormat = "unified"
+
+// diffSide is the table or the export file to compare.
+type diffSide struct {
+	// name is the table name or the file name.
+	name  string
+	table *model.Table
+	r     itemReader
+	close func()
+}
+
+func openDiffSide(
+	ctx context.Context,
+	c client.NewClient,
+	side,
+	tableName,
+	fileName string,
+	open func(string) (io.ReadCloser, error),
+) (*diffSide, error) {
+	switch {
+	case len(tableName) == 0 && len(fileName) == 0:
+		return nil, fmt.Errorf("required either --%s-table or --%s-file option", side, side)
+	case len(tableName) != 0 && len(fileName) != 0:
+		return nil, fmt.Errorf("use either --%s-table or --%s-file option", side, side)
+	}
+
+	if len(fileName) != 0 {
+		in, err := open(fileName)
+		if err != nil {
+			return nil, err
+		}
+		gr, err := gzip.NewReader(in)
+		if err != nil {
+			in.Close()
+			return nil, fmt.Errorf("invalid export file: %v", err)
+		}
+		r, table, err := newExportItemReader(gr)
+		if err != nil {
+			in.Close()
+			return nil, err
+		}
+		return &diffSide{
+			name:  fileName,
+			table: table,
+			r:     r,
+			close: func() {
+				gr.Close()
+				in.Close()
+			},
+		}, nil
+	}
+
+	ctx = context.WithValue(ctx, newClientKey, c.CreateInstance())
+	table, err := describeTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newCopyItemReader(ctx, table, CopyOption{})
+	if err != nil {
+		return nil, err
+	}
+	return &diffSide{
+		name:  tableName,
+		table: table,
+		r:     r,
+		close: r.close,
+	}, nil
+}
+
+// diffEntry is the item which differs. source is nil if the item is only in the target,
+// and target is nil if the item is only in the source.
+type diffEntry struct {
+	key    map[string]types.AttributeValue
+	source map[string]types.AttributeValue
+	target map[string]types.AttributeValue
+}
+
+func itemKeyString(key map[string]types.AttributeValue) (string, error) {
+	b, err := json.Marshal(dynamoDBJSONItem(key))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// compareKey compares the keys by the partition key, and then by the sort key.
+func compareKey(table *model.Table, a, b map[string]types.AttributeValue) int {
+	if c, _ := compareAttributeValue(a[table.PartitionKey.Name], b[table.PartitionKey.Name]); c != 0 {
+		return c
+	}
+	if table.SortKey == nil {
+		return 0
+	}
+	c, _ := compareAttributeValue(a[table.SortKey.Name], b[table.SortKey.Name])
+	return c
+}
+
+// diffItems reads all items of the source, and compares the items of the target with them by the key.
+// The entries are sorted by the key, and the number of the same items is returned together.
+func diffItems(table *model.Table, source, target itemReader) ([]*diffEntry, int, error) {
+	type keyedItem struct {
+		key  map[string]types.AttributeValue
+		item map[string]types.AttributeValue
+	}
+	items := make(map[string]*keyedItem)
+	for {
+		item, err := source.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		key, err := keyFromItem(table, item)
+		if err != nil {
+			return nil, 0, err
+		}
+		k, err := itemKeyString(key)
+		if err != nil {
+			return nil, 0, err
+		}
+		items[k] = &keyedItem{key: key, item: item}
+	}
+
+	var entries []*diffEntry
+	same := 0
+	for {
+		item, err := target.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		key, err := keyFromItem(table, item)
+		if err != nil {
+			return nil, 0, err
+		}
+		k, err := itemKeyString(key)
+		if err != nil {
+			return nil, 0, err
+		}
+		s, ok := items[k]
+		if !ok {
+			entries = append(entries, &diffEntry{key: key, target: item})
+			continue
+		}
+		delete(items, k)
+		if equalItem(s.item, item) {
+			same++
+			continue
+		}
+		entries = append(entries, &diffEntry{key: key, source: s.item, target: item})
+	}
+	for _, s := range items {
+		entries = append(entries, &diffEntry{key: s.key, source: s.item})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return compareKey(table, entries[i].key, entries[j].key) < 0
+	})
+	return entries, same, nil
+}
+
+func plainValue(av types.AttributeValue) (interface{}, error) {
+	var v interface{}
+	if err := attributevalue.Unmarshal(av, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func plainItem(item map[string]types.AttributeValue) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, len(item))
+	if err := attributevalue.UnmarshalMap(item, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// diffAttribute is the value of the attribute in each side. The value is nil if the attribute does not exist.
+type diffAttribute struct {
+	Source interface{} `json:"source,omitempty"`
+	Target interface{} `json:"target,omitempty"`
+}
+
+// changedAttributes returns the names of the attributes which differ, in order of the name.
+func changedAttributes(source, target map[string]types.AttributeValue) []string {
+	var names []string
+	for k, v := range source {
+		if w, ok := target[k]; !ok || !equalAttributeValue(v, w) {
+			names = append(names, k)
+		}
+	}
+	for k := range target {
+		if _, ok := source[k]; !ok {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func diffAttributes(source, target map[string]types.AttributeValue) (map[string]*diffAttribute, error) {
+	res := make(map[string]*diffAttribute)
+	for _, name := range changedAttributes(source, target) {
+		a := &diffAttribute{}
+		var err error
+		if v, ok := source[name]; ok {
+			if a.Source, err = plainValue(v); err != nil {
+				return nil, err
+			}
+		}
+		if v, ok := target[name]; ok {
+			if a.Target, err = plainValue(v); err != nil {
+				return nil, err
+			}
+		}
+		res[name] = a
+	}
+	return res, nil
+}
+
+// diffResult shows the items only in the target as added, the items only in the source as removed,
+// and the attributes of the changed items.
+func diffResult(entries []*diffEntry, same int) (map[string]interface{}, error) {
+	added := make([]map[string]interface{}, 0)
+	removed := make([]map[string]interface{}, 0)
+	changed := make([]map[string]interface{}, 0)
+	for _, e := range entries {
+		key, err := plainItem(e.key)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case e.source == nil:
+			item, err := plainItem(e.target)
+			if err != nil {
+				return nil, err
+			}
+			added = append(added, map[string]interface{}{"key": key, "item": item})
+		case e.target == nil:
+			item, err := plainItem(e.source)
+			if err != nil {
+				return nil, err
+			}
+			removed = append(removed, map[string]interface{}{"key": key, "item": item})
+		default:
+			attrs, err := diffAttributes(e.source, e.target)
+			if err != nil {
+				return nil, err
+			}
+			changed = append(changed, map[string]interface{}{"key": key, "attributes": attrs})
+		}
+	}
+	return map[string]interface{}{
+		"added":   added,
+		"removed": removed,
+		"changed": changed,
+		"same":    same,
+	}, nil
+}
+
+func compactJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// printUnifiedDiff shows the entries like diff -u. The removed and the added items are shown as a whole,
+// and the changed items are shown by the attributes.
+func printUnifiedDiff(w io.Writer, source, target string, entries []*diffEntry) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", source, target)
+	line := func(prefix string, v interface{}, suffix string) error {
+		s, err := compactJSON(v)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "%s%s%s\n", prefix, s, suffix)
+		return nil
+	}
+	attribute := func(prefix, name string, item map[string]types.AttributeValue) error {
+		av, ok := item[name]
+		if !ok {
+			return nil
+		}
+		v, err := plainValue(av)
+		if err != nil {
+			return err
+		}
+		return line(prefix+name+": ", v, "")
+	}
+	for _, e := range entries {
+		key, err := plainItem(e.key)
+		if err != nil {
+			return err
+		}
+		if err := line("@@ ", key, " @@"); err != nil {
+			return err
+		}
+		if e.source == nil || e.target == nil {
+			prefix, item := "+", e.target
+			if e.target == nil {
+				prefix, item = "-", e.source
+			}
+			m, err := plainItem(item)
+			if err != nil {
+				return err
+			}
+			if err := line(prefix, m, ""); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, name := range changedAttributes(e.source, e.target) {
+			if err := attribute("-", name, e.source); err != nil {
+				return err
+			}
+			if err := attribute("+", name, e.target); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeReconcileFiles writes the items to put and the keys to delete, which make the target the same as
+// the source, in DynamoDB JSON Lines. They can be read by put and delete.
+func writeReconcileFiles(
+	entries []*diffEntry,
+	putFile,
+	deleteFile string,
+	create func(string) (io.WriteCloser, error),
+) error {
+	write := func(fileName string, f func(*diffEntry) map[string]types.AttributeValue) error {
+		if len(fileName) == 0 {
+			return nil
+		}
+		out, err := create(fileName)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		e := json.NewEncoder(out)
+		for i := range entries {
+			if item := f(entries[i]); item != nil {
+				if err := e.Encode(dynamoDBJSONItem(item)); err != nil {
+					return err
+				}
+			}
+		}
+		return out.Close()
+	}
+	err := write(putFile, func(e *diffEntry) map[string]types.AttributeValue {
+		return e.source
+	})
+	if err != nil {
+		return err
+	}
+	return write(deleteFile, func(e *diffEntry) map[string]types.AttributeValue {
+		if e.source != nil {
+			return nil
+		}
+		return e.key
+	})
+}
+
+// Diff compares the items of the source and the target by the primary key. Each of them is the table
+// or the file written by export. The items only in the target are shown as added, and the items only
+// in the source are shown as removed.
+func (i *Instance) Diff(
+	ctx context.Context,
+	w io.Writer,
+	open func(string) (io.ReadCloser, error),
+	create func(string) (io.WriteCloser, error),
+	option DiffOption,
+) error {
+	if len(option.PutFile) != 0 && option.PutFile == option.DeleteFile {
+		return fmt.Errorf("use different files for --put-file and --delete-file")
+	}
+	source, err := openDiffSide(ctx, i.NewClient, "source", option.SourceTable, option.SourceFile, open)
+	if err != nil {
+		return err
+	}
+	defer source.close()
+	c := option.Target
+	if c == nil {
+		c = i.NewClient
+	}
+	target, err := openDiffSide(ctx, c, "target", option.TargetTable, option.TargetFile, open)
+	if err != nil {
+		return err
+	}
+	defer target.close()
+	if !sameKeySchema(source.table, target.table) {
+		return fmt.Errorf("the key schema of %s is different from %s", target.name, source.name)
+	}
+
+	entries, same, err := diffItems(source.table, source.r, target.r)
+	if err != nil {
+		return err
+	}
+	if err := writeReconcileFiles(entries, option.PutFile, option.DeleteFile, create); err != nil {
+		return err
+	}
+	if strings.ToLower(option.Output) == diffUnifiedFormat {
+		return printUnifiedDiff(w, source.name, target.name, entries)
+	}
+	res, err := diffResult(entries, same)
+	if err != nil {
+		return err
+	}
+	return printJSON(w, res)
+}
@@ -0,0 +1,289 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/hirano00o/edy/mocks"
+	"github.com/hirano00o/edy/model"
+)
+
+func TestInstance_Diff(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		files  map[string]string
+		option DiffOption
+	}
+	sourceItems := []map[string]types.AttributeValue{
+		{
+			"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "P0"},
+			"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S0"},
+		},
+		exportItemsFixture[0],
+		exportItemsFixture[1],
+	}
+	targetItems := []map[string]types.AttributeValue{
+		{
+			"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "P3"},
+			"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S3"},
+		},
+		{
+			"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "P1"},
+			"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S1"},
+			"TEST_ATTRIBUTE":           &types.AttributeValueMemberN{Value: "2"},
+			"NEW_ATTRIBUTE":            &types.AttributeValueMemberS{Value: "X"},
+		},
+		{
+			"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "P2"},
+			"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S2"},
+			"TEST_ATTRIBUTE":           &types.AttributeValueMemberSS{Value: []string{"B", "A"}},
+		},
+	}
+	targetFile := exportFixture(
+		t,
+		exportTableFixture,
+		`{"TEST_PARTITION_ATTRIBUTE":{"S":"P3"},"TEST_SORT_ATTRIBUTE":{"S":"S3"}}`,
+		`{"NEW_ATTRIBUTE":{"S":"X"},"TEST_ATTRIBUTE":{"N":"2"},"TEST_PARTITION_ATTRIBUTE":{"S":"P1"},`+
+			`"TEST_SORT_ATTRIBUTE":{"S":"S1"}}`,
+		`{"TEST_ATTRIBUTE":{"SS":["B","A"]},"TEST_PARTITION_ATTRIBUTE":{"S":"P2"},"TEST_SORT_ATTRIBUTE":{"S":"S2"}}`,
+	)
+	tableMock := func(t *testing.T, tableName string, items []map[string]types.AttributeValue) *mocks.MockDynamoDBAPI {
+		t.Helper()
+
+		m := new(mocks.MockDynamoDBAPI)
+		m.On("CreateInstance").Return(m)
+		m.DescribeTableAPIClient.On("DescribeTable", mock.Anything, &dynamodb.DescribeTableInput{
+			TableName: aws.String(tableName),
+		}).Return(activeDescribeTableOutputFixture(t), nil)
+		m.ScanAPIClient.On("Scan", mock.Anything, mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return *input.Segment == 0
+		})).Return(&dynamodb.ScanOutput{Items: items}, nil)
+		m.ScanAPIClient.On("Scan", mock.Anything, mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return *input.Segment != 0
+		})).Return(&dynamodb.ScanOutput{}, nil)
+		return m
+	}
+	tests := []struct {
+		name      string
+		args      args
+		mocking   func(t *testing.T) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI)
+		wantW     string
+		wantFiles map[string]string
+		wantErr   bool
+	}{
+		{
+			name: "Diff tables",
+			args: args{
+				ctx: context.Background(),
+				option: DiffOption{
+					SourceTable: "TEST",
+					TargetTable: "TEST2",
+				},
+			},
+			mocking: func(t *testing.T) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				return tableMock(t, "TEST", sourceItems), tableMock(t, "TEST2", targetItems)
+			},
+			wantW: `{
+  "added": [
+    {
+      "item": {
+        "TEST_PARTITION_ATTRIBUTE": "P3",
+        "TEST_SORT_ATTRIBUTE": "S3"
+      },
+      "key": {
+        "TEST_PARTITION_ATTRIBUTE": "P3",
+        "TEST_SORT_ATTRIBUTE": "S3"
+      }
+    }
+  ],
+  "changed": [
+    {
+      "attributes": {
+        "NEW_ATTRIBUTE": {
+          "target": "X"
+        },
+        "TEST_ATTRIBUTE": {
+          "source": 1,
+          "target": 2
+        }
+      },
+      "key": {
+        "TEST_PARTITION_ATTRIBUTE": "P1",
+        "TEST_SORT_ATTRIBUTE": "S1"
+      }
+    }
+  ],
+  "removed": [
+    {
+      "item": {
+        "TEST_PARTITION_ATTRIBUTE": "P0",
+        "TEST_SORT_ATTRIBUTE": "S0"
+      },
+      "key": {
+        "TEST_PARTITION_ATTRIBUTE": "P0",
+        "TEST_SORT_ATTRIBUTE": "S0"
+      }
+    }
+  ],
+  "same": 1
+}
+`,
+		},
+		{
+			name: "Diff table and export file in unified format with reconcile files",
+			args: args{
+				ctx:   context.Background(),
+				files: map[string]string{"TEST.jsonl.gz": targetFile},
+				option: DiffOption{
+					SourceTable: "TEST",
+					TargetFile:  "TEST.jsonl.gz",
+					Output:      "unified",
+					PutFile:     "put.jsonl",
+					DeleteFile:  "delete.jsonl",
+				},
+			},
+			mocking: func(t *testing.T) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				m := tableMock(t, "TEST", sourceItems)
+				return m, m
+			},
+			wantW: "--- TEST\n+++ TEST.jsonl.gz\n" +
+				"@@ {\"TEST_PARTITION_ATTRIBUTE\":\"P0\",\"TEST_SORT_ATTRIBUTE\":\"S0\"} @@\n" +
+				"-{\"TEST_PARTITION_ATTRIBUTE\":\"P0\",\"TEST_SORT_ATTRIBUTE\":\"S0\"}\n" +
+				"@@ {\"TEST_PARTITION_ATTRIBUTE\":\"P1\",\"TEST_SORT_ATTRIBUTE\":\"S1\"} @@\n" +
+				"+NEW_ATTRIBUTE: \"X\"\n" +
+				"-TEST_ATTRIBUTE: 1\n" +
+				"+TEST_ATTRIBUTE: 2\n" +
+				"@@ {\"TEST_PARTITION_ATTRIBUTE\":\"P3\",\"TEST_SORT_ATTRIBUTE\":\"S3\"} @@\n" +
+				"+{\"TEST_PARTITION_ATTRIBUTE\":\"P3\",\"TEST_SORT_ATTRIBUTE\":\"S3\"}\n",
+			wantFiles: map[string]string{
+				"put.jsonl": `{"TEST_PARTITION_ATTRIBUTE":{"S":"P0"},"TEST_SORT_ATTRIBUTE":{"S":"S0"}}` + "\n" +
+					exportItem1Fixture + "\n",
+				"delete.jsonl": `{"TEST_PARTITION_ATTRIBUTE":{"S":"P3"},"TEST_SORT_ATTRIBUTE":{"S":"S3"}}` + "\n",
+			},
+		},
+		{
+			name: "Error key schema is different",
+			args: args{
+				ctx: context.Background(),
+				files: map[string]string{"TEST.jsonl.gz": exportFixture(t, &model.Table{
+					Name:         "TEST",
+					PartitionKey: &model.Key{Name: "ID", TypeStr: "N"},
+				})},
+				option: DiffOption{
+					SourceFile:  "TEST.jsonl.gz",
+					TargetTable: "TEST",
+				},
+			},
+			mocking: func(t *testing.T) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				m := tableMock(t, "TEST", nil)
+				return m, m
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error scan",
+			args: args{
+				ctx: context.Background(),
+				option: DiffOption{
+					SourceTable: "TEST",
+					TargetTable: "TEST2",
+				},
+			},
+			mocking: func(t *testing.T) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				dst := new(mocks.MockDynamoDBAPI)
+				dst.On("CreateInstance").Return(dst)
+				dst.DescribeTableAPIClient.On("DescribeTable", mock.Anything, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST2"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+				dst.ScanAPIClient.On("Scan", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("error"))
+				return tableMock(t, "TEST", sourceItems), dst
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error neither table nor file of the target",
+			args: args{
+				ctx: context.Background(),
+				option: DiffOption{
+					SourceTable: "TEST",
+				},
+			},
+			mocking: func(t *testing.T) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				m := tableMock(t, "TEST", sourceItems)
+				return m, m
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error same reconcile files",
+			args: args{
+				ctx: context.Background(),
+				option: DiffOption{
+					SourceTable: "TEST",
+					TargetTable: "TEST2",
+					PutFile:     "reconcile.jsonl",
+					DeleteFile:  "reconcile.jsonl",
+				},
+			},
+			mocking: func(t *testing.T) (*mocks.MockDynamoDBAPI, *mocks.MockDynamoDBAPI) {
+				t.Helper()
+
+				return new(mocks.MockDynamoDBAPI), new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, dst := tt.mocking(t)
+			i := &Instance{
+				NewClient: src,
+			}
+			w := &bytes.Buffer{}
+			files := make(map[string]*bytes.Buffer)
+			open := fileFixture(t, func(fileName string) (string, error) {
+				s, ok := tt.args.files[fileName]
+				if !ok {
+					return "", fmt.Errorf("no such file: %s", fileName)
+				}
+				return gzipFixture(t, s), nil
+			})
+			create := func(fileName string) (io.WriteCloser, error) {
+				files[fileName] = &bytes.Buffer{}
+				return nopWriteCloser{files[fileName]}, nil
+			}
+			tt.args.option.Target = dst
+			err := i.Diff(tt.args.ctx, w, open, create, tt.args.option)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("Diff() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			for name, want := range tt.wantFiles {
+				if got := files[name].String(); got != want {
+					t.Errorf("Diff() %s = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,414 @@
+package edy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/client"
+	"github.com/hirano00o/edy/model"
+)
+
+// dryRunRequest is the request which would be sent in dry-run mode.
+type dryRunRequest struct {
+	Operation                 string                            `json:"operation"`
+	TableName                 string                            `json:"tableName,omitempty"`
+	Chunk                     int                               `json:"chunk,omitempty"`
+	Count                     int                               `json:"count,omitempty"`
+	Requests                  []map[string]interface{}          `json:"requests,omitempty"`
+	Item                      map[string]interface{}            `json:"item,omitempty"`
+	Key                       map[string]interface{}            `json:"key,omitempty"`
+	ConditionExpression       string                            `json:"conditionExpression,omitempty"`
+	ExpressionAttributeNames  map[string]string                 `json:"expressionAttributeNames,omitempty"`
+	ExpressionAttributeValues map[string]map[string]interface{} `json:"expressionAttributeValues,omitempty"`
+	ReturnValues              string                            `json:"returnValues,omitempty"`
+	Statement                 string                            `json:"statement,omitempty"`
+	Parameters                []map[string]interface{}          `json:"parameters,omitempty"`
+	// Input is the request of the table operation such as CreateTable.
+	Input interface{} `json:"input,omitempty"`
+}
+
+// dryRunClient prints the write requests instead of sending them.
+// The read requests such as DescribeTable are sent as usual.
+// The keys of the requests are validated against the key schema of the table.
+type dryRunClient struct {
+	client.DynamoDB
+	w      io.Writer
+	tables map[string]*model.Table
+	chunk  int
+}
+
+func newDryRunClient(cli client.DynamoDB, w io.Writer) *dryRunClient {
+	return &dryRunClient{
+		DynamoDB: cli,
+		w:        w,
+		tables:   make(map[string]*model.Table),
+	}
+}
+
+// createInstance creates the client, which does not write in dry-run mode.
+func (i *Instance) createInstance(w io.Writer) client.DynamoDB {
+	cli := i.NewClient.CreateInstance()
+	if i.DryRun {
+		return newDryRunClient(cli, w)
+	}
+	return cli
+}
+
+func (c *dryRunClient) table(ctx context.Context, tableName string) (*model.Table, error) {
+	if t, ok := c.tables[tableName]; ok {
+		return t, nil
+	}
+	t, err := describeTable(context.WithValue(ctx, newClientKey, c.DynamoDB), tableName)
+	if err != nil {
+		return nil, err
+	}
+	c.tables[tableName] = t
+	return t, nil
+}
+
+func (c *dryRunClient) validate(ctx context.Context, tableName string, item map[string]types.AttributeValue) error {
+	t, err := c.table(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	if err := validateKey(t, item); err != nil {
+		b, _ := json.Marshal(dynamoDBJSONItem(item))
+		return fmt.Errorf("%v: %s", err, string(b))
+	}
+	return nil
+}
+
+func (c *dryRunClient) print(req *dryRunRequest) error {
+	b, err := json.MarshalIndent(req, "", strings.Repeat(" ", 2))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.w, "%s\n", string(b))
+	return nil
+}
+
+func expressionValuesJSON(values map[string]types.AttributeValue) map[string]map[string]interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	m := make(map[string]map[string]interface{}, len(values))
+	for k := range values {
+		m[k] = dynamoDBJSONOf(values[k])
+	}
+	return m
+}
+
+func sqlParametersJSON(params []types.AttributeValue) []map[string]interface{} {
+	if len(params) == 0 {
+		return nil
+	}
+	res := make([]map[string]interface{}, len(params))
+	for i := range params {
+		res[i] = dynamoDBJSONOf(params[i])
+	}
+	return res
+}
+
+func (c *dryRunClient) PutItem(
+	ctx context.Context,
+	params *dynamodb.PutItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.PutItemOutput, error) {
+	tableName := aws.ToString(params.TableName)
+	if err := c.validate(ctx, tableName, params.Item); err != nil {
+		return nil, err
+	}
+	err := c.print(&dryRunRequest{
+		Operation:                 "PutItem",
+		TableName:                 tableName,
+		Item:                      dynamoDBJSONItem(params.Item),
+		ConditionExpression:       aws.ToString(params.ConditionExpression),
+		ExpressionAttributeNames:  params.ExpressionAttributeNames,
+		ExpressionAttributeValues: expressionValuesJSON(params.ExpressionAttributeValues),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (c *dryRunClient) BatchWriteItem(
+	ctx context.Context,
+	params *dynamodb.BatchWriteItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.BatchWriteItemOutput, error) {
+	for tableName, requests := range params.RequestItems {
+		c.chunk++
+		req := &dryRunRequest{
+			Operation: "BatchWriteItem",
+			TableName: tableName,
+			Chunk:     c.chunk,
+			Count:     len(requests),
+			Requests:  make([]map[string]interface{}, len(requests)),
+		}
+		for i := range requests {
+			switch {
+			case requests[i].PutRequest != nil:
+				item := requests[i].PutRequest.Item
+				if err := c.validate(ctx, tableName, item); err != nil {
+					return nil, err
+				}
+				req.Requests[i] = map[string]interface{}{
+					"PutRequest": map[string]interface{}{"Item": dynamoDBJSONItem(item)},
+				}
+			case requests[i].DeleteRequest != nil:
+				key := requests[i].DeleteRequest.Key
+				if err := c.validate(ctx, tableName, key); err != nil {
+					return nil, err
+				}
+				req.Requests[i] = map[string]interface{}{
+					"DeleteRequest": map[string]interface{}{"Key": dynamoDBJSONItem(key)},
+				}
+			}
+		}
+		if err := c.print(req); err != nil {
+			return nil, err
+		}
+	}
+	return &dynamodb.BatchWriteItemOutput{
+		UnprocessedItems: map[string][]types.WriteRequest{},
+	}, nil
+}
+
+func (c *dryRunClient) DeleteItem(
+	ctx context.Context,
+	params *dynamodb.DeleteItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.DeleteItemOutput, error) {
+	tableName := aws.ToString(params.TableName)
+	if err := c.validate(ctx, tableName, params.Key); err != nil {
+		return nil, err
+	}
+	err := c.print(&dryRunRequest{
+		Operation:                 "DeleteItem",
+		TableName:                 tableName,
+		Key:                       dynamoDBJSONItem(params.Key),
+		ConditionExpression:       aws.ToString(params.ConditionExpression),
+		ExpressionAttributeNames:  params.ExpressionAttributeNames,
+		ExpressionAttributeValues: expressionValuesJSON(params.ExpressionAttributeValues),
+		ReturnValues:              string(params.ReturnValues),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (c *dryRunClient) TransactWriteItems(
+	ctx context.Context,
+	params *dynamodb.TransactWriteItemsInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.TransactWriteItemsOutput, error) {
+	req := &dryRunRequest{
+		Operation: "TransactWriteItems",
+		Count:     len(params.TransactItems),
+		Requests:  make([]map[string]interface{}, len(params.TransactItems)),
+	}
+	for i, item := range params.TransactItems {
+		var op string
+		var tableName *string
+		m := make(map[string]interface{})
+		var values map[string]types.AttributeValue
+		var names map[string]string
+		switch {
+		case item.Put != nil:
+			op, tableName = "Put", item.Put.TableName
+			if err := c.validate(ctx, aws.ToString(tableName), item.Put.Item); err != nil {
+				return nil, err
+			}
+			m["Item"] = dynamoDBJSONItem(item.Put.Item)
+			m["ConditionExpression"] = aws.ToString(item.Put.ConditionExpression)
+			names, values = item.Put.ExpressionAttributeNames, item.Put.ExpressionAttributeValues
+		case item.Update != nil:
+			op, tableName = "Update", item.Update.TableName
+			if err := c.validate(ctx, aws.ToString(tableName), item.Update.Key); err != nil {
+				return nil, err
+			}
+			m["Key"] = dynamoDBJSONItem(item.Update.Key)
+			m["UpdateExpression"] = aws.ToString(item.Update.UpdateExpression)
+			m["ConditionExpression"] = aws.ToString(item.Update.ConditionExpression)
+			names, values = item.Update.ExpressionAttributeNames, item.Update.ExpressionAttributeValues
+		case item.Delete != nil:
+			op, tableName = "Delete", item.Delete.TableName
+			if err := c.validate(ctx, aws.ToString(tableName), item.Delete.Key); err != nil {
+				return nil, err
+			}
+			m["Key"] = dynamoDBJSONItem(item.Delete.Key)
+			m["ConditionExpression"] = aws.ToString(item.Delete.ConditionExpression)
+			names, values = item.Delete.ExpressionAttributeNames, item.Delete.ExpressionAttributeValues
+		case item.ConditionCheck != nil:
+			op, tableName = "ConditionCheck", item.ConditionCheck.TableName
+			if err := c.validate(ctx, aws.ToString(tableName), item.ConditionCheck.Key); err != nil {
+				return nil, err
+			}
+			m["Key"] = dynamoDBJSONItem(item.ConditionCheck.Key)
+			m["ConditionExpression"] = aws.ToString(item.ConditionCheck.ConditionExpression)
+			names, values = item.ConditionCheck.ExpressionAttributeNames, item.ConditionCheck.ExpressionAttributeValues
+		}
+		m["TableName"] = aws.ToString(tableName)
+		for k, v := range m {
+			if s, ok := v.(string); ok && len(s) == 0 {
+				delete(m, k)
+			}
+		}
+		if len(names) != 0 {
+			m["ExpressionAttributeNames"] = names
+		}
+		if len(values) != 0 {
+			m["ExpressionAttributeValues"] = expressionValuesJSON(values)
+		}
+		req.Requests[i] = map[string]interface{}{op: m}
+	}
+	if err := c.print(req); err != nil {
+		return nil, err
+	}
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+// ExecuteStatement sends SELECT as usual, and prints the other statements.
+func (c *dryRunClient) ExecuteStatement(
+	ctx context.Context,
+	params *dynamodb.ExecuteStatementInput,
+	optFns ...func(*dynamodb.Options),
+) (*dynamodb.ExecuteStatementOutput, error) {
+	if client.IsReadStatement(aws.ToString(params.Statement)) {
+		return c.DynamoDB.ExecuteStatement(ctx, params, optFns...)
+	}
+	err := c.print(&dryRunRequest{
+		Operation:  "ExecuteStatement",
+		Statement:  aws.ToString(params.Statement),
+		Parameters: sqlParametersJSON(params.Parameters),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.ExecuteStatementOutput{}, nil
+}
+
+// BatchExecuteStatement sends the statements as usual if all of them are SELECT, otherwise prints them.
+func (c *dryRunClient) BatchExecuteStatement(
+	ctx context.Context,
+	params *dynamodb.BatchExecuteStatementInput,
+	optFns ...func(*dynamodb.Options),
+) (*dynamodb.BatchExecuteStatementOutput, error) {
+	read := true
+	for i := range params.Statements {
+		read = read && client.IsReadStatement(aws.ToString(params.Statements[i].Statement))
+	}
+	if read {
+		return c.DynamoDB.BatchExecuteStatement(ctx, params, optFns...)
+	}
+	req := &dryRunRequest{
+		Operation: "BatchExecuteStatement",
+		Count:     len(params.Statements),
+		Requests:  make([]map[string]interface{}, len(params.Statements)),
+	}
+	for i := range params.Statements {
+		m := map[string]interface{}{"Statement": aws.ToString(params.Statements[i].Statement)}
+		if p := sqlParametersJSON(params.Statements[i].Parameters); p != nil {
+			m["Parameters"] = p
+		}
+		req.Requests[i] = m
+	}
+	if err := c.print(req); err != nil {
+		return nil, err
+	}
+	return &dynamodb.BatchExecuteStatementOutput{}, nil
+}
+
+// createdTable returns the key schema of the table to be created.
+func createdTable(params *dynamodb.CreateTableInput) *model.Table {
+	attr := make(map[string]model.AttributeType)
+	for _, a := range params.AttributeDefinitions {
+		attr[aws.ToString(a.AttributeName)] = model.AttributeTypeStr(a.AttributeType).Name()
+	}
+	t := &model.Table{Name: aws.ToString(params.TableName)}
+	for _, k := range params.KeySchema {
+		name := aws.ToString(k.AttributeName)
+		key := &model.Key{Name: name, Type: attr[name], TypeStr: attr[name].String()}
+		switch k.KeyType {
+		case types.KeyTypeHash:
+			t.PartitionKey = key
+		case types.KeyTypeRange:
+			t.SortKey = key
+		}
+	}
+	return t
+}
+
+func (c *dryRunClient) CreateTable(
+	_ context.Context,
+	params *dynamodb.CreateTableInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.CreateTableOutput, error) {
+	err := c.print(&dryRunRequest{
+		Operation: "CreateTable",
+		TableName: aws.ToString(params.TableName),
+		Input:     params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The table does not exist in dry-run mode, so the following writes are validated against the created schema.
+	c.tables[aws.ToString(params.TableName)] = createdTable(params)
+	return &dynamodb.CreateTableOutput{}, nil
+}
+
+func (c *dryRunClient) UpdateTimeToLive(
+	_ context.Context,
+	params *dynamodb.UpdateTimeToLiveInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	err := c.print(&dryRunRequest{
+		Operation: "UpdateTimeToLive",
+		TableName: aws.ToString(params.TableName),
+		Input:     params.TimeToLiveSpecification,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.UpdateTimeToLiveOutput{}, nil
+}
+
+func (c *dryRunClient) DeleteTable(
+	_ context.Context,
+	params *dynamodb.DeleteTableInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.DeleteTableOutput, error) {
+	err := c.print(&dryRunRequest{
+		Operation: "DeleteTable",
+		TableName: aws.ToString(params.TableName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.DeleteTableOutput{}, nil
+}
+
+func (c *dryRunClient) UpdateTable(
+	_ context.Context,
+	params *dynamodb.UpdateTableInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.UpdateTableOutput, error) {
+	err := c.print(&dryRunRequest{
+		Operation: "UpdateTable",
+		TableName: aws.ToString(params.TableName),
+		Input:     params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.UpdateTableOutput{}, nil
+}
@@ -0,0 +1,202 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/mocks"
+)
+
+func Test_dryRunClient_BatchWriteItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   *dynamodb.BatchWriteItemInput
+		wantW   string
+		wantErr bool
+	}{
+		{
+			name: "Print put requests",
+			input: &dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]types.WriteRequest{
+					"TEST": {
+						{
+							PutRequest: &types.PutRequest{
+								Item: map[string]types.AttributeValue{
+									"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "T1"},
+									"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S1"},
+									"TEST_ATTRIBUTE_2":         &types.AttributeValueMemberN{Value: "1"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantW: jsonFixture(t, map[string]interface{}{
+				"operation": "BatchWriteItem",
+				"tableName": "TEST",
+				"chunk":     1,
+				"count":     1,
+				"requests": []map[string]interface{}{
+					{
+						"PutRequest": map[string]interface{}{
+							"Item": map[string]interface{}{
+								"TEST_PARTITION_ATTRIBUTE": map[string]string{"S": "T1"},
+								"TEST_SORT_ATTRIBUTE":      map[string]string{"S": "S1"},
+								"TEST_ATTRIBUTE_2":         map[string]string{"N": "1"},
+							},
+						},
+					},
+				},
+			}),
+		},
+		{
+			name: "Print delete requests",
+			input: &dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]types.WriteRequest{
+					"TEST": {
+						{
+							DeleteRequest: &types.DeleteRequest{
+								Key: map[string]types.AttributeValue{
+									"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "T1"},
+									"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S1"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantW: jsonFixture(t, map[string]interface{}{
+				"operation": "BatchWriteItem",
+				"tableName": "TEST",
+				"chunk":     1,
+				"count":     1,
+				"requests": []map[string]interface{}{
+					{
+						"DeleteRequest": map[string]interface{}{
+							"Key": map[string]interface{}{
+								"TEST_PARTITION_ATTRIBUTE": map[string]string{"S": "T1"},
+								"TEST_SORT_ATTRIBUTE":      map[string]string{"S": "S1"},
+							},
+						},
+					},
+				},
+			}),
+		},
+		{
+			name: "Error sort key is missing",
+			input: &dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]types.WriteRequest{
+					"TEST": {
+						{
+							PutRequest: &types.PutRequest{
+								Item: map[string]types.AttributeValue{
+									"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "T1"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error partition key type is different",
+			input: &dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]types.WriteRequest{
+					"TEST": {
+						{
+							PutRequest: &types.PutRequest{
+								Item: map[string]types.AttributeValue{
+									"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberN{Value: "1"},
+									"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S1"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			m := new(mocks.MockDynamoDBAPI)
+			m.DescribeTableAPIClient.On("DescribeTable", context.WithValue(ctx, newClientKey, m), &dynamodb.DescribeTableInput{
+				TableName: aws.String("TEST"),
+			}).Return(describeTableOutputFixture(t, false), nil)
+			w := &bytes.Buffer{}
+			c := newDryRunClient(m, w)
+
+			_, err := c.BatchWriteItem(ctx, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BatchWriteItem() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); !jsonEqual(t, gotW, tt.wantW) {
+				t.Errorf("BatchWriteItem() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			m.BatchWriteItemClient.AssertNotCalled(t, "BatchWriteItem")
+		})
+	}
+}
+
+func Test_dryRunClient_PutItem(t *testing.T) {
+	ctx := context.Background()
+	m := new(mocks.MockDynamoDBAPI)
+	m.DescribeTableAPIClient.On("DescribeTable", context.WithValue(ctx, newClientKey, m), &dynamodb.DescribeTableInput{
+		TableName: aws.String("TEST"),
+	}).Return(describeTableOutputFixture(t, false), nil)
+	w := &bytes.Buffer{}
+	c := newDryRunClient(m, w)
+
+	_, err := c.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String("TEST"),
+		Item: map[string]types.AttributeValue{
+			"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "T1"},
+			"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S1"},
+		},
+		ConditionExpression:      aws.String("attribute_not_exists (#0)"),
+		ExpressionAttributeNames: map[string]string{"#0": "TEST_PARTITION_ATTRIBUTE"},
+	})
+	if err != nil {
+		t.Fatalf("PutItem() error = %v", err)
+	}
+	want := jsonFixture(t, map[string]interface{}{
+		"operation": "PutItem",
+		"tableName": "TEST",
+		"item": map[string]interface{}{
+			"TEST_PARTITION_ATTRIBUTE": map[string]string{"S": "T1"},
+			"TEST_SORT_ATTRIBUTE":      map[string]string{"S": "S1"},
+		},
+		"conditionExpression":      "attribute_not_exists (#0)",
+		"expressionAttributeNames": map[string]string{"#0": "TEST_PARTITION_ATTRIBUTE"},
+	})
+	if gotW := w.String(); !jsonEqual(t, gotW, want) {
+		t.Errorf("PutItem() gotW = %v, want %v", gotW, want)
+	}
+	m.PutItemClient.AssertNotCalled(t, "PutItem")
+}
+
+// jsonEqual compares JSON regardless of the order of the keys.
+func jsonEqual(t *testing.T, got, want string) bool {
+	t.Helper()
+	if len(got) == 0 || len(want) == 0 {
+		return got == want
+	}
+	var g, w interface{}
+	if err := json.Unmarshal([]byte(got), &g); err != nil {
+		t.Fatalf("json unmarshal error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("json unmarshal error: %v", err)
+	}
+	return reflect.DeepEqual(g, w)
+}
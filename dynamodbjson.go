@@ -0,0 +1,335 @@
+package edy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/model"
+)
+
+var dynamoDBJSONTypes = map[string]struct{}{
+	"S":    {},
+	"N":    {},
+	"B":    {},
+	"BOOL": {},
+	"NULL": {},
+	"M":    {},
+	"L":    {},
+	"SS":   {},
+	"NS":   {},
+	"BS":   {},
+}
+
+// unwrapDynamoDBJSON extracts the items from the output of aws dynamodb scan, query or get-item.
+// Items is unwrapped only if the object has Count or ScannedCount as well, so that the plain item
+// which has the attribute Items is not mistaken for the output.
+func unwrapDynamoDBJSON(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	if items, ok := m["Items"].([]interface{}); ok && isScanOutput(m, items) {
+		return items
+	}
+	if item, ok := m["Item"].(map[string]interface{}); ok && isDynamoDBJSONItem(item) {
+		return item
+	}
+	return v
+}
+
+func isScanOutput(m map[string]interface{}, items []interface{}) bool {
+	_, count := m["Count"]
+	_, scannedCount := m["ScannedCount"]
+	if !count && !scannedCount {
+		return false
+	}
+	for i := range items {
+		item, ok := items[i].(map[string]interface{})
+		if !ok || !isDynamoDBJSONItem(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDynamoDBJSONValue(v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return false
+	}
+	for k := range m {
+		if _, ok := dynamoDBJSONTypes[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// hasDynamoDBJSONShape checks the type of the value as well as the type name,
+// such as {"S":"a"}, so that the plain map which has the key S is not mistaken for DynamoDB JSON.
+func hasDynamoDBJSONShape(v interface{}) bool {
+	if !isDynamoDBJSONValue(v) {
+		return false
+	}
+	isNumber := func(v interface{}) bool {
+		switch v.(type) {
+		case string, json.Number:
+			return true
+		}
+		return false
+	}
+	each := func(v interface{}, f func(interface{}) bool) bool {
+		l, ok := v.([]interface{})
+		if !ok {
+			return false
+		}
+		for i := range l {
+			if !f(l[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	isString := func(v interface{}) bool {
+		_, ok := v.(string)
+		return ok
+	}
+	for typ, value := range v.(map[string]interface{}) {
+		switch typ {
+		case "S", "B":
+			return isString(value)
+		case "N":
+			return isNumber(value)
+		case "BOOL", "NULL":
+			_, ok := value.(bool)
+			return ok
+		case "M":
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return false
+			}
+			for k := range m {
+				if !hasDynamoDBJSONShape(m[k]) {
+					return false
+				}
+			}
+			return true
+		case "L":
+			return each(value, hasDynamoDBJSONShape)
+		case "SS", "BS":
+			return each(value, isString)
+		case "NS":
+			return each(value, isNumber)
+		}
+	}
+	return false
+}
+
+func isDynamoDBJSONItem(item map[string]interface{}) bool {
+	if len(item) == 0 {
+		return false
+	}
+	for k := range item {
+		if !hasDynamoDBJSONShape(item[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+func analyseDynamoDBJSONItem(item map[string]interface{}) (map[string]types.AttributeValue, error) {
+	m := make(map[string]types.AttributeValue, len(item))
+	for k := range item {
+		v, err := analyseDynamoDBJSONValue(item[k])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", k, err)
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+func analyseDynamoDBJSONValue(v interface{}) (types.AttributeValue, error) {
+	if !isDynamoDBJSONValue(v) {
+		return nil, fmt.Errorf("invalid dynamodb json format: %v", v)
+	}
+	for typ, value := range v.(map[string]interface{}) {
+		switch typ {
+		case "S":
+			s, ok := value.(string)
+			if !ok {
+				break
+			}
+			return &types.AttributeValueMemberS{Value: s}, nil
+		case "N":
+			n, err := dynamoDBJSONNumber(value)
+			if err != nil {
+				return nil, err
+			}
+			return &types.AttributeValueMemberN{Value: n}, nil
+		case "B":
+			b, err := dynamoDBJSONBinary(value)
+			if err != nil {
+				return nil, err
+			}
+			return &types.AttributeValueMemberB{Value: b}, nil
+		case "BOOL":
+			b, ok := value.(bool)
+			if !ok {
+				break
+			}
+			return &types.AttributeValueMemberBOOL{Value: b}, nil
+		case "NULL":
+			b, ok := value.(bool)
+			if !ok || !b {
+				break
+			}
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		case "M":
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				break
+			}
+			mm, err := analyseDynamoDBJSONItem(m)
+			if err != nil {
+				return nil, err
+			}
+			return &types.AttributeValueMemberM{Value: mm}, nil
+		case "L":
+			l, ok := value.([]interface{})
+			if !ok {
+				break
+			}
+			ll := make([]types.AttributeValue, len(l))
+			for i := range l {
+				av, err := analyseDynamoDBJSONValue(l[i])
+				if err != nil {
+					return nil, err
+				}
+				ll[i] = av
+			}
+			return &types.AttributeValueMemberL{Value: ll}, nil
+		case "SS":
+			l, ok := value.([]interface{})
+			if !ok {
+				break
+			}
+			ss := make([]string, len(l))
+			for i := range l {
+				s, ok := l[i].(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid dynamodb json format, SS: %v", value)
+				}
+				ss[i] = s
+			}
+			return &types.AttributeValueMemberSS{Value: ss}, nil
+		case "NS":
+			l, ok := value.([]interface{})
+			if !ok {
+				break
+			}
+			ns := make([]string, len(l))
+			for i := range l {
+				n, err := dynamoDBJSONNumber(l[i])
+				if err != nil {
+					return nil, err
+				}
+				ns[i] = n
+			}
+			return &types.AttributeValueMemberNS{Value: ns}, nil
+		case "BS":
+			l, ok := value.([]interface{})
+			if !ok {
+				break
+			}
+			bs := make([][]byte, len(l))
+			for i := range l {
+				b, err := dynamoDBJSONBinary(l[i])
+				if err != nil {
+					return nil, err
+				}
+				bs[i] = b
+			}
+			return &types.AttributeValueMemberBS{Value: bs}, nil
+		}
+		return nil, fmt.Errorf("invalid dynamodb json format, %s: %v", typ, value)
+	}
+	return nil, fmt.Errorf("invalid dynamodb json format: %v", v)
+}
+
+// dynamoDBJSONNumber accepts both of the wire format "N":"1" and the loose format "N":1.
+func dynamoDBJSONNumber(v interface{}) (string, error) {
+	var n string
+	switch t := v.(type) {
+	case string:
+		n = strings.TrimSpace(t)
+	case json.Number:
+		n = t.String()
+	default:
+		return "", fmt.Errorf("invalid dynamodb json format, N: %v", v)
+	}
+	if _, err := strconv.ParseFloat(n, 64); err != nil {
+		return "", fmt.Errorf("invalid dynamodb json format, N: %v", v)
+	}
+	return n, nil
+}
+
+func dynamoDBJSONBinary(v interface{}) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid dynamodb json format, B: %v", v)
+	}
+	b, err := model.DecodeBinary(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dynamodb json format, %v", err)
+	}
+	return b, nil
+}
+
+// dynamoDBJSONItem converts the item to DynamoDB JSON such as {"ID":{"N":"1"}}.
+func dynamoDBJSONItem(item map[string]types.AttributeValue) map[string]interface{} {
+	m := make(map[string]interface{}, len(item))
+	for k := range item {
+		m[k] = dynamoDBJSONOf(item[k])
+	}
+	return m
+}
+
+func dynamoDBJSONOf(av types.AttributeValue) map[string]interface{} {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return map[string]interface{}{"S": v.Value}
+	case *types.AttributeValueMemberN:
+		return map[string]interface{}{"N": v.Value}
+	case *types.AttributeValueMemberB:
+		return map[string]interface{}{"B": model.EncodeBinary(v.Value)}
+	case *types.AttributeValueMemberBOOL:
+		return map[string]interface{}{"BOOL": v.Value}
+	case *types.AttributeValueMemberNULL:
+		return map[string]interface{}{"NULL": v.Value}
+	case *types.AttributeValueMemberM:
+		return map[string]interface{}{"M": dynamoDBJSONItem(v.Value)}
+	case *types.AttributeValueMemberL:
+		l := make([]interface{}, len(v.Value))
+		for i := range v.Value {
+			l[i] = dynamoDBJSONOf(v.Value[i])
+		}
+		return map[string]interface{}{"L": l}
+	case *types.AttributeValueMemberSS:
+		return map[string]interface{}{"SS": v.Value}
+	case *types.AttributeValueMemberNS:
+		return map[string]interface{}{"NS": v.Value}
+	case *types.AttributeValueMemberBS:
+		bs := make([]string, len(v.Value))
+		for i := range v.Value {
+			bs[i] = model.EncodeBinary(v.Value[i])
+		}
+		return map[string]interface{}{"BS": bs}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+package edy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func Test_analyseDynamoDBJSONItem(t *testing.T) {
+	type args struct {
+		item string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]types.AttributeValue
+		wantErr bool
+	}{
+		{
+			name: "Analyse all types",
+			args: args{
+				item: "{\"S\":{\"S\":\"T\"},\"N\":{\"N\":\"1.5\"},\"B\":{\"B\":\"dGVzdA==\"},\"BOOL\":{\"BOOL\":true}," +
+					"\"NULL\":{\"NULL\":true},\"M\":{\"M\":{\"K\":{\"N\":2}}},\"L\":{\"L\":[{\"S\":\"T\"}]}," +
+					"\"SS\":{\"SS\":[\"T1\",\"T2\"]},\"NS\":{\"NS\":[\"1\",\"2\"]},\"BS\":{\"BS\":[\"dGVzdA==\"]}}",
+			},
+			want: map[string]types.AttributeValue{
+				"S":    &types.AttributeValueMemberS{Value: "T"},
+				"N":    &types.AttributeValueMemberN{Value: "1.5"},
+				"B":    &types.AttributeValueMemberB{Value: []byte("test")},
+				"BOOL": &types.AttributeValueMemberBOOL{Value: true},
+				"NULL": &types.AttributeValueMemberNULL{Value: true},
+				"M": &types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"K": &types.AttributeValueMemberN{Value: "2"},
+					},
+				},
+				"L": &types.AttributeValueMemberL{
+					Value: []types.AttributeValue{
+						&types.AttributeValueMemberS{Value: "T"},
+					},
+				},
+				"SS": &types.AttributeValueMemberSS{Value: []string{"T1", "T2"}},
+				"NS": &types.AttributeValueMemberNS{Value: []string{"1", "2"}},
+				"BS": &types.AttributeValueMemberBS{Value: [][]byte{[]byte("test")}},
+			},
+		},
+		{
+			name: "Error invalid number",
+			args: args{
+				item: "{\"ID\":{\"N\":\"one\"}}",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error invalid base64",
+			args: args{
+				item: "{\"ID\":{\"B\":\"!\"}}",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error unknown type",
+			args: args{
+				item: "{\"ID\":{\"X\":\"1\"}}",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error mismatch type",
+			args: args{
+				item: "{\"ID\":{\"S\":1}}",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := newJSONItemDecoder(strings.NewReader(tt.args.item), jsonInputType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, _, err := d.next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := analyseDynamoDBJSONItem(m)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("analyseDynamoDBJSONItem() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("analyseDynamoDBJSONItem() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
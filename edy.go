@@ -4,6 +4,8 @@ import (
 	"context"
 	"io"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
 	"github.com/hirano00o/edy/client"
 )
 
@@ -24,8 +26,25 @@ type Edy interface {
 		projection string,
 		output string,
 	) error
-	DescribeTable(ctx context.Context, w io.Writer, tableName string) error
-	Put(ctx context.Context, w io.Writer, tableName, item, fileName string, f func(string) (string, error)) error
+	DescribeTable(ctx context.Context, w io.Writer, tableName, output string) error
+	ScanExport(
+		ctx context.Context,
+		w io.Writer,
+		dir string,
+		f func(string) (io.ReadCloser, error),
+		filterCondition,
+		projection,
+		output string,
+	) error
+	Put(
+		ctx context.Context,
+		w io.Writer,
+		tableName,
+		item,
+		fileName string,
+		f func(string) (io.ReadCloser, error),
+		option PutOption,
+	) error
 	Delete(
 		ctx context.Context,
 		w io.Writer,
@@ -33,10 +52,231 @@ type Edy interface {
 		partitionValue,
 		sortValue,
 		fileName string,
-		f func(string) (string, error),
+		f func(string) (io.ReadCloser, error),
+		option DeleteOption,
+	) error
+	Transact(
+		ctx context.Context,
+		w io.Writer,
+		fileName string,
+		f func(string) (io.ReadCloser, error),
+		option TransactOption,
+	) error
+	TransactGet(
+		ctx context.Context,
+		w io.Writer,
+		fileName string,
+		f func(string) (io.ReadCloser, error),
+		option TransactGetOption,
 	) error
+	SQL(
+		ctx context.Context,
+		w io.Writer,
+		statement,
+		fileName string,
+		f func(string) (io.ReadCloser, error),
+		option SQLOption,
+	) error
+	CreateTable(
+		ctx context.Context,
+		w io.Writer,
+		tableName,
+		fileName string,
+		f func(string) (io.ReadCloser, error),
+	) error
+	DeleteTable(ctx context.Context, w io.Writer, tableName string, option DeleteTableOption) error
+	Truncate(ctx context.Context, w io.Writer, tableName string, option TruncateOption) error
+	List(ctx context.Context, w io.Writer, option ListOption) error
+	UpdateTable(ctx context.Context, w io.Writer, tableName string, option UpdateTableOption) error
+	DescribeTTL(ctx context.Context, w io.Writer, tableName string) error
+	UpdateTTL(ctx context.Context, w io.Writer, tableName, attributeName string, enabled bool) error
+	Export(
+		ctx context.Context,
+		w io.Writer,
+		tableName,
+		fileName string,
+		f func(string) (io.WriteCloser, error),
+		option ExportOption,
+	) error
+	Import(
+		ctx context.Context,
+		w io.Writer,
+		tableName,
+		fileName string,
+		f func(string) (io.ReadCloser, error),
+		option ImportOption,
+	) error
+	Copy(ctx context.Context, w io.Writer, sourceTable, targetTable string, option CopyOption) error
+	Diff(
+		ctx context.Context,
+		w io.Writer,
+		open func(string) (io.ReadCloser, error),
+		create func(string) (io.WriteCloser, error),
+		option DiffOption,
+	) error
+}
+
+type PutOption struct {
+	// InputFormat is json, dynamodb-json, csv or aws-export. Empty means detecting it from the input.
+	// aws-export reads the directory of the table export to S3 which is specified as the input file.
+	InputFormat string
+	// ArrayType is set or list, which JSON array of string or number is converted to. Default is set.
+	ArrayType string
+	// SetAttrs and ListAttrs are comma separated attribute names whose array is converted to set or list.
+	SetAttrs  string
+	ListAttrs string
+	// EmptyCell is skip or null, how the empty cell of csv is handled. Default is skip.
+	EmptyCell string
+	// IfNotExists puts the item only if the item of the same key does not exist.
+	IfNotExists bool
+	// Condition is the condition to put the item, which is written in the same format as the filter.
+	Condition string
+	// TTLIn sets the TTL attribute of the table to now + TTLIn in epoch seconds, such as 7d, 2w and 36h.
+	TTLIn string
+	// SkipInvalid puts the valid items even if some items do not match the key schema.
+	// Otherwise, all items are validated before the first write and nothing is put if there is the invalid item.
+	SkipInvalid bool
+	// Confirmation is asked before putting the items of the input file into the protected table.
+	Confirmation
+}
+
+type DeleteOption struct {
+	// InputFormat is json or dynamodb-json. Empty means detecting it from the input.
+	InputFormat string
+	// Where is the filter condition of the items to delete, which are found by scan.
+	Where string
+	// FromQuery finds the items to delete by query. The partition value and the sort value are
+	// used as the partition key and the sort key condition of query.
+	FromQuery bool
+	Confirmation
+	// Condition is the condition to delete the item, which is written in the same format as the filter.
+	Condition string
+	// ReturnOld shows the deleted items.
+	ReturnOld bool
+}
+
+type TransactOption struct {
+	// ClientRequestToken makes the transaction idempotent. The same token within 10 minutes is not applied twice.
+	ClientRequestToken string
+	// Confirmation is asked before writing into the protected table.
+	Confirmation
+}
+
+type TransactGetOption struct {
+	// Output is the format to show the result, which is the same as scan and query.
+	Output string
+	// Warning shows the keys which match no item.
+	Warning io.Writer
+}
+
+type SQLOption struct {
+	// Parameters is JSON array of the parameters which replace ? in the statement.
+	Parameters string
+	// Output is the format to show the result, which is the same as scan and query.
+	Output string
+	// Confirmation is asked before INSERT, UPDATE and DELETE into the protected table.
+	Confirmation
+}
+
+type DeleteTableOption struct {
+	Confirmation
+}
+
+type TruncateOption struct {
+	Confirmation
+	// Recreate deletes and creates the table instead of deleting the items, which is fast
+	// but the table gets a new ARN and stream.
+	Recreate bool
+	// Progress shows the progress such as the number of deleted items.
+	Progress io.Writer
+}
+
+type ListOption struct {
+	// Prefix and Match filter the table names by the prefix and the regular expression.
+	Prefix string
+	Match  string
+	// Describe shows the key schema, the item count, the size, the billing mode and the status of each table.
+	Describe bool
+	// Parallel is the number of tables described at the same time. Default is 4.
+	Parallel int
+	// Output is the format to show the result, which is the same as scan and query.
+	Output string
+}
+
+type UpdateTableOption struct {
+	// BillingMode is PAY_PER_REQUEST or PROVISIONED.
+	BillingMode string
+	// ReadCapacity and WriteCapacity are the provisioned capacity units of the table. 0 means unchanged.
+	ReadCapacity  int64
+	WriteCapacity int64
+	// GSICapacity is the provisioned capacity units of the indexes written as INDEX:READ:WRITE.
+	GSICapacity []string
+	// AddGSI is the index to create, which is written in JSON or YAML in the same format as gsi of describe.
+	AddGSI string
+	// DeleteGSI is the name of the index to delete.
+	DeleteGSI string
+	// Stream is the stream view type to enable the stream, or DISABLED to disable it.
+	Stream string
+	// TableClass is STANDARD or STANDARD_INFREQUENT_ACCESS.
+	TableClass string
+	// NoWait returns without waiting until the table and the indexes are ACTIVE.
+	NoWait bool
+	// Progress shows the status of the table and the indexes while waiting.
+	Progress io.Writer
+}
+
+type ExportOption struct {
+	// Progress shows the number of exported items.
+	Progress io.Writer
+}
+
+type ImportOption struct {
+	// Confirmation is asked before importing into the protected table.
+	Confirmation
+	// Progress shows the number of imported items.
+	Progress io.Writer
+}
+
+type CopyOption struct {
+	// Target is the client of the target table. Nil means the same as the source.
+	Target client.NewClient
+	// Partition and Sort are the partition value and the sort key condition to copy the items found by query.
+	// Otherwise the items are found by scan.
+	Partition string
+	Sort      string
+	// Filter is the filter condition of the items to copy.
+	Filter string
+	// Segments is the number of segments of the parallel scan. Default is 4.
+	Segments int
+	// KeyPrefix replaces the prefix of the key written as NAME:FROM:TO, such as ID:stg-:dev-.
+	KeyPrefix []string
+	// TransformKey rewrites the key of each item after KeyPrefix.
+	TransformKey func(key map[string]types.AttributeValue) (map[string]types.AttributeValue, error)
+	// Confirmation is asked before copying into the protected table.
+	Confirmation
+	// Progress shows the number of copied items.
+	Progress io.Writer
+}
+
+type DiffOption struct {
+	// SourceTable or SourceFile is the source to compare, and TargetTable or TargetFile is the target.
+	// The file is written by export.
+	SourceTable string
+	SourceFile  string
+	TargetTable string
+	TargetFile  string
+	// Target is the client of the target table. Nil means the same as the source.
+	Target client.NewClient
+	// Output is json or unified. Default is json.
+	Output string
+	// PutFile and DeleteFile are the files of the items to put and the keys to delete in DynamoDB JSON Lines,
+	// which make the target the same as the source.
+	PutFile    string
+	DeleteFile string
 }
 
 type Instance struct {
 	client.NewClient
+	// DryRun prints the write requests instead of sending them.
+	DryRun bool
 }
@@ -0,0 +1,266 @@
+package edy
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/client"
+	"github.com/hirano00o/edy/model"
+)
+
+const (
+	exportFormat  = "edy-export"
+	exportVersion = 1
+)
+
+// exportHeader is the first line of the export file, which is followed by the items in DynamoDB JSON Lines.
+type exportHeader struct {
+	Format  string       `json:"format"`
+	Version int          `json:"version"`
+	Table   *model.Table `json:"table"`
+}
+
+// exportItems writes the header and all items of the table found by scan to w.
+func exportItems(ctx context.Context, w io.Writer, table *model.Table, progress io.Writer) (int, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	e := json.NewEncoder(w)
+	err := e.Encode(&exportHeader{
+		Format:  exportFormat,
+		Version: exportVersion,
+		Table:   table,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	input, err := scanInput(table.Name, "", "")
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	paginator := dynamodb.NewScanPaginator(cli, input)
+	for paginator.HasMorePages() {
+		res, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("%v (%d items have been exported)", err, count)
+		}
+		for i := range res.Items {
+			if err := e.Encode(dynamoDBJSONItem(res.Items[i])); err != nil {
+				return 0, err
+			}
+		}
+		count += len(res.Items)
+		progressf(progress, "%d items have been exported from %s\n", count, table.Name)
+	}
+	return count, nil
+}
+
+// exportItemReader reads the items following the header of the export file.
+type exportItemReader struct {
+	d     *json.Decoder
+	count int
+}
+
+func newExportItemReader(r io.Reader) (*exportItemReader, *model.Table, error) {
+	d := json.NewDecoder(r)
+	var h exportHeader
+	if err := d.Decode(&h); err != nil {
+		return nil, nil, fmt.Errorf("invalid export file: %v", err)
+	}
+	if h.Format != exportFormat || h.Table == nil {
+		return nil, nil, fmt.Errorf("invalid export file, the header is not found")
+	}
+	if h.Version > exportVersion {
+		return nil, nil, fmt.Errorf("unsupported export file version: %d", h.Version)
+	}
+	return &exportItemReader{d: d}, h.Table, nil
+}
+
+func (r *exportItemReader) next() (map[string]types.AttributeValue, error) {
+	var item map[string]interface{}
+	if err := r.d.Decode(&item); err == io.EOF {
+		return nil, io.EOF
+	} else if err != nil {
+		return nil, fmt.Errorf("invalid export file, item %d: %v", r.count+1, err)
+	}
+	r.count++
+	av, err := analyseDynamoDBJSONItem(item)
+	if err != nil {
+		return nil, fmt.Errorf("item %d: %v", r.count, err)
+	}
+	return av, nil
+}
+
+func (r *exportItemReader) isList() bool {
+	return true
+}
+
+func sameKeySchema(a, b *model.Table) bool {
+	same := func(x, y *model.Key) bool {
+		if x == nil || y == nil {
+			return x == y
+		}
+		return x.Name == y.Name && x.TypeStr == y.TypeStr
+	}
+	return same(a.PartitionKey, b.PartitionKey) && same(a.SortKey, b.SortKey)
+}
+
+// prepareImportTable creates the table from the schema of source if it does not exist,
+// otherwise checks that the key schema of the existing table is the same.
+func prepareImportTable(ctx context.Context, spec *model.Table, source string, dryRun bool, progress io.Writer) error {
+	t, err := describeTable(ctx, spec.Name)
+	var rnf *types.ResourceNotFoundException
+	if errors.As(err, &rnf) {
+		progressf(progress, "Creating %s\n", spec.Name)
+		return createTable(ctx, spec, !dryRun)
+	}
+	if err != nil {
+		return err
+	}
+	if !sameKeySchema(t, spec) {
+		return fmt.Errorf("the key schema of %s is different from %s", t.Name, source)
+	}
+	return nil
+}
+
+// writeItems writes all items of r to the table in chunks of BatchWriteItem.
+// done is the word such as imported, which is used in the progress and the result.
+func writeItems(
+	ctx context.Context,
+	tableName string,
+	r itemReader,
+	done string,
+	progress io.Writer,
+) (map[string]interface{}, error) {
+	bw := newBatchWriter(ctx, tableName)
+	written := 0
+	for {
+		item, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v (%d items have been %s)", err, bw.written, done)
+		}
+		if err := bw.add(types.WriteRequest{PutRequest: &types.PutRequest{Item: item}}); err != nil {
+			return nil, err
+		}
+		if bw.written != written {
+			written = bw.written
+			progressf(progress, "%d items have been %s into %s\n", written, done, tableName)
+		}
+	}
+	unprocessed, err := bw.close()
+	if err != nil {
+		return nil, err
+	}
+	if bw.written != written {
+		progressf(progress, "%d items have been %s into %s\n", bw.written, done, tableName)
+	}
+
+	res := unprocessedResult(unprocessed)
+	res[done] = bw.written - len(unprocessed)
+	return res, nil
+}
+
+// Export writes the schema and all items of the table to the gzip compressed file.
+// The file is written to w if fileName is -, otherwise the number of exported items is shown.
+func (i *Instance) Export(
+	ctx context.Context,
+	w io.Writer,
+	tableName,
+	fileName string,
+	f func(string) (io.WriteCloser, error),
+	option ExportOption,
+) error {
+	if len(fileName) == 0 {
+		return fmt.Errorf("required --output-file option")
+	}
+	cli := i.NewClient.CreateInstance()
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	t, err := describeTableDetail(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	out, err := f(fileName)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	gw := gzip.NewWriter(out)
+	count, err := exportItems(ctx, gw, t, option.Progress)
+	if err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if fileName == "-" {
+		return nil
+	}
+	return printJSON(w, map[string]interface{}{
+		"exported": count,
+		"file":     fileName,
+	})
+}
+
+// Import writes the items of the export file to the table. The table is created from the exported schema
+// if it does not exist. tableName overrides the name in the export file, so that the table can be cloned.
+func (i *Instance) Import(
+	ctx context.Context,
+	w io.Writer,
+	tableName,
+	fileName string,
+	f func(string) (io.ReadCloser, error),
+	option ImportOption,
+) error {
+	if len(fileName) == 0 {
+		return fmt.Errorf("required --input-file option")
+	}
+	in, err := f(fileName)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	gr, err := gzip.NewReader(in)
+	if err != nil {
+		return fmt.Errorf("invalid export file: %v", err)
+	}
+	defer gr.Close()
+	r, spec, err := newExportItemReader(gr)
+	if err != nil {
+		return err
+	}
+	if len(tableName) != 0 {
+		spec.Name = tableName
+	}
+	if !i.DryRun {
+		if err := option.confirmWrite(spec.Name, "imported"); err != nil {
+			return err
+		}
+	}
+
+	cli := i.createInstance(w)
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	if err := prepareImportTable(ctx, spec, "the exported table", i.DryRun, option.Progress); err != nil {
+		return err
+	}
+	res, err := writeItems(ctx, spec.Name, r, "imported", option.Progress)
+	if err != nil {
+		return err
+	}
+	return printJSON(w, res)
+}
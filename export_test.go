@@ -0,0 +1,381 @@
+package edy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/hirano00o/edy/mocks"
+	"github.com/hirano00o/edy/model"
+)
+
+// exportFixture returns the content of the export file which has the table and the items in DynamoDB JSON.
+func exportFixture(t *testing.T, table *model.Table, items ...string) string {
+	t.Helper()
+
+	b, err := json.Marshal(&exportHeader{Format: exportFormat, Version: exportVersion, Table: table})
+	if err != nil {
+		t.Fatalf("json marshal error: %v", err)
+	}
+	return string(b) + "\n" + strings.Join(items, "\n") + "\n"
+}
+
+func gzipFixture(t *testing.T, s string) string {
+	t.Helper()
+
+	var b bytes.Buffer
+	gw := gzip.NewWriter(&b)
+	if _, err := gw.Write([]byte(s)); err != nil {
+		t.Fatalf("gzip write error: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("gzip close error: %v", err)
+	}
+	return b.String()
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+var exportTableFixture = &model.Table{
+	Name: "TEST",
+	PartitionKey: &model.Key{
+		Name:    "TEST_PARTITION_ATTRIBUTE",
+		TypeStr: "S",
+	},
+	SortKey: &model.Key{
+		Name:    "TEST_SORT_ATTRIBUTE",
+		TypeStr: "S",
+	},
+}
+
+var exportItemsFixture = []map[string]types.AttributeValue{
+	{
+		"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "P1"},
+		"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S1"},
+		"TEST_ATTRIBUTE":           &types.AttributeValueMemberN{Value: "1"},
+	},
+	{
+		"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "P2"},
+		"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S2"},
+		"TEST_ATTRIBUTE":           &types.AttributeValueMemberSS{Value: []string{"A", "B"}},
+	},
+}
+
+const (
+	exportItem1Fixture = `{"TEST_ATTRIBUTE":{"N":"1"},"TEST_PARTITION_ATTRIBUTE":{"S":"P1"},` +
+		`"TEST_SORT_ATTRIBUTE":{"S":"S1"}}`
+	exportItem2Fixture = `{"TEST_ATTRIBUTE":{"SS":["A","B"]},"TEST_PARTITION_ATTRIBUTE":{"S":"P2"},` +
+		`"TEST_SORT_ATTRIBUTE":{"S":"S2"}}`
+)
+
+func TestInstance_Export(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		tableName string
+		fileName  string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		mocking      func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI
+		wantW        string
+		wantFile     string
+		wantProgress string
+		wantErr      bool
+	}{
+		{
+			name: "Export",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				fileName:  "TEST.jsonl.gz",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+				describeTableDetailMock(t, ctx, m)
+				m.ScanAPIClient.On("Scan", ctx, &dynamodb.ScanInput{
+					TableName: aws.String("TEST"),
+				}).Return(&dynamodb.ScanOutput{Items: exportItemsFixture, Count: 2}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"exported\": 2,\n  \"file\": \"TEST.jsonl.gz\"\n}\n",
+			wantFile: exportFixture(t, &model.Table{
+				Arn:    "TEST_ARN",
+				Name:   "TEST",
+				Status: "ACTIVE",
+				PartitionKey: &model.Key{
+					Name:    "TEST_PARTITION_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				SortKey: &model.Key{
+					Name:    "TEST_SORT_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				TTL:                 &model.TTL{Status: "DISABLED"},
+				PointInTimeRecovery: &model.PointInTimeRecovery{Status: "DISABLED"},
+				ItemCount:           1,
+			}, exportItem1Fixture, exportItem2Fixture),
+			wantProgress: "2 items have been exported from TEST\n",
+		},
+		{
+			name: "Export without file name",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: m,
+			}
+			w := &bytes.Buffer{}
+			file := &bytes.Buffer{}
+			progress := &bytes.Buffer{}
+			f := func(string) (io.WriteCloser, error) {
+				return nopWriteCloser{file}, nil
+			}
+			err := i.Export(tt.args.ctx, w, tt.args.tableName, tt.args.fileName, f, ExportOption{Progress: progress})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Export() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("Export() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			if tt.wantErr {
+				return
+			}
+			gr, err := gzip.NewReader(file)
+			if err != nil {
+				t.Fatalf("gzip reader error: %v", err)
+			}
+			gotFile, err := io.ReadAll(gr)
+			if err != nil {
+				t.Fatalf("gzip read error: %v", err)
+			}
+			if string(gotFile) != tt.wantFile {
+				t.Errorf("Export() gotFile = %v, want %v", string(gotFile), tt.wantFile)
+			}
+			if gotProgress := progress.String(); gotProgress != tt.wantProgress {
+				t.Errorf("Export() gotProgress = %v, want %v", gotProgress, tt.wantProgress)
+			}
+		})
+	}
+}
+
+func TestInstance_Import(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		tableName string
+		file      string
+		dryRun    bool
+	}
+	createTableInput := &dynamodb.CreateTableInput{
+		TableName: aws.String("TEST"),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("TEST_PARTITION_ATTRIBUTE"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("TEST_SORT_ATTRIBUTE"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("TEST_PARTITION_ATTRIBUTE"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("TEST_SORT_ATTRIBUTE"), KeyType: types.KeyTypeRange},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	}
+	batchWriteItemInput := &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{
+			"TEST": {
+				{PutRequest: &types.PutRequest{Item: exportItemsFixture[0]}},
+				{PutRequest: &types.PutRequest{Item: exportItemsFixture[1]}},
+			},
+		},
+	}
+	sourceTable := *exportTableFixture
+	sourceTable.Name = "SOURCE"
+	tests := []struct {
+		name         string
+		args         args
+		mocking      func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI
+		wantW        string
+		wantProgress string
+		wantErr      bool
+	}{
+		{
+			name: "Import into new table",
+			args: args{
+				ctx:  context.Background(),
+				file: exportFixture(t, exportTableFixture, exportItem1Fixture, exportItem2Fixture),
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				input := &dynamodb.DescribeTableInput{TableName: aws.String("TEST")}
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, input).
+					Return(nil, &types.ResourceNotFoundException{}).Once()
+				m.CreateTableClient.On("CreateTable", ctx, createTableInput).Return(&dynamodb.CreateTableOutput{}, nil)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, input).
+					Return(activeDescribeTableOutputFixture(t), nil)
+				m.BatchWriteItemClient.On("BatchWriteItem", ctx, batchWriteItemInput).Return(&dynamodb.BatchWriteItemOutput{
+					UnprocessedItems: map[string][]types.WriteRequest{},
+				}, nil)
+
+				return m
+			},
+			wantW:        "{\n  \"imported\": 2,\n  \"unprocessed\": []\n}\n",
+			wantProgress: "Creating TEST\n2 items have been imported into TEST\n",
+		},
+		{
+			name: "Import into existing table with the table name",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				file:      exportFixture(t, &sourceTable, exportItem1Fixture, exportItem2Fixture),
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+				m.BatchWriteItemClient.On("BatchWriteItem", ctx, batchWriteItemInput).Return(&dynamodb.BatchWriteItemOutput{
+					UnprocessedItems: map[string][]types.WriteRequest{},
+				}, nil)
+
+				return m
+			},
+			wantW:        "{\n  \"imported\": 2,\n  \"unprocessed\": []\n}\n",
+			wantProgress: "2 items have been imported into TEST\n",
+		},
+		{
+			name: "Import into new table in dry-run mode",
+			args: args{
+				ctx:    context.Background(),
+				file:   exportFixture(t, exportTableFixture, exportItem1Fixture),
+				dryRun: true,
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", mock.Anything, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(nil, &types.ResourceNotFoundException{})
+
+				return m
+			},
+			wantW: jsonFixture(t, &dryRunRequest{
+				Operation: "CreateTable",
+				TableName: "TEST",
+				Input:     createTableInput,
+			}) + jsonFixture(t, &dryRunRequest{
+				Operation: "BatchWriteItem",
+				TableName: "TEST",
+				Chunk:     1,
+				Count:     1,
+				Requests: []map[string]interface{}{
+					{"PutRequest": map[string]interface{}{"Item": dynamoDBJSONItem(exportItemsFixture[0])}},
+				},
+			}) + "{\n  \"imported\": 1,\n  \"unprocessed\": []\n}\n",
+			wantProgress: "Creating TEST\n1 items have been imported into TEST\n",
+		},
+		{
+			name: "Import into table whose key schema is different",
+			args: args{
+				ctx: context.Background(),
+				file: exportFixture(t, &model.Table{
+					Name:         "TEST",
+					PartitionKey: &model.Key{Name: "ID", TypeStr: "N"},
+				}),
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "Import file which is not exported by edy",
+			args: args{
+				ctx:  context.Background(),
+				file: exportItem1Fixture + "\n",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: m,
+				DryRun:    tt.args.dryRun,
+			}
+			w := &bytes.Buffer{}
+			progress := &bytes.Buffer{}
+			f := fileFixture(t, func(string) (string, error) {
+				return gzipFixture(t, tt.args.file), nil
+			})
+			err := i.Import(tt.args.ctx, w, tt.args.tableName, "TEST.jsonl.gz", f, ImportOption{Progress: progress})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Import() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("Import() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			if gotProgress := progress.String(); gotProgress != tt.wantProgress {
+				t.Errorf("Import() gotProgress = %v, want %v", gotProgress, tt.wantProgress)
+			}
+			m.BatchWriteItemClient.AssertExpectations(t)
+		})
+	}
+}
@@ -21,10 +21,19 @@ const (
 	logicalOperator
 )
 
-func analyseFilterCondition(
-	condition string,
-) (*expression.ConditionBuilder, error) {
-	var c expression.ConditionBuilder
+// filterTerm is a condition of the filter, which is joined to the preceding terms by the logical operator.
+type filterTerm struct {
+	logicalOperator model.LogicalOperator
+	operator        model.ComparisonOperator
+	key             string
+	keyType         model.AttributeType
+	values          []string
+	not             bool
+}
+
+// parseFilterCondition splits the condition into the terms, which are evaluated from left to right.
+func parseFilterCondition(condition string) ([]filterTerm, error) {
+	var terms []filterTerm
 	var err error
 	s := strings.Split(condition, " ")
 	var op model.ComparisonOperator
@@ -57,8 +66,7 @@ func analyseFilterCondition(
 			if err != nil {
 				return nil, err
 			}
-			if (conditionKeyType.String() == new(model.SS).String() ||
-				conditionKeyType.String() == new(model.NS).String()) &&
+			if isSetType(conditionKeyType) &&
 				!(op == model.IN || op == model.EQ || op == model.EXISTS) {
 				return nil, fmt.Errorf("%s operand can not use type %s", op.String(), conditionKeyType.String())
 			}
@@ -76,9 +84,7 @@ func analyseFilterCondition(
 				if len(conditionValue) == 2 {
 					nextState = join
 				}
-			case op == model.IN || (op == model.EQ &&
-				(conditionKeyType.String() == new(model.SS).String() ||
-					conditionKeyType.String() == new(model.NS).String())):
+			case op == model.IN || (op == model.EQ && isSetType(conditionKeyType)):
 				_, err = model.ConvertToLogicalOperator(s[i])
 				if err == nil {
 					i--
@@ -92,18 +98,14 @@ func analyseFilterCondition(
 			}
 		case join:
 			i--
-			_c, err := makeExpression(op, conditionKeyType, conditionValue, conditionKey, notCondition)
-			if err != nil {
-				return nil, err
-			}
-			switch lOp {
-			case model.AND:
-				c = c.And(*_c)
-			case model.OR:
-				c = c.Or(*_c)
-			default:
-				c = *_c
-			}
+			terms = append(terms, filterTerm{
+				logicalOperator: lOp,
+				operator:        op,
+				key:             conditionKey,
+				keyType:         conditionKeyType,
+				values:          conditionValue,
+				not:             notCondition,
+			})
 			op = model.ComparisonOperator(0)
 			conditionKey = ""
 			conditionKeyType = nil
@@ -120,28 +122,80 @@ func analyseFilterCondition(
 			return nil, fmt.Errorf("unknown condition error: %s", condition)
 		}
 	}
-	if nextState != join && op != model.IN &&
-		!(op == model.EQ &&
-			(conditionKeyType.String() == new(model.SS).String() ||
-				conditionKeyType.String() == new(model.NS).String())) {
+	if nextState != join && op != model.IN && !(op == model.EQ && isSetType(conditionKeyType)) {
 		return nil, fmt.Errorf("invalid condition: %s", condition)
 	}
-	_c, err := makeExpression(op, conditionKeyType, conditionValue, conditionKey, notCondition)
+	terms = append(terms, filterTerm{
+		logicalOperator: lOp,
+		operator:        op,
+		key:             conditionKey,
+		keyType:         conditionKeyType,
+		values:          conditionValue,
+		not:             notCondition,
+	})
+	return terms, nil
+}
+
+// joinFilterTerms joins the results of the n terms from left to right by the logical operator of each term,
+// which is how the filter is evaluated both by DynamoDB and locally.
+func joinFilterTerms[T any](
+	n int,
+	term func(i int) (T, model.LogicalOperator, error),
+	and, or func(T, T) T,
+) (T, error) {
+	var res T
+	for i := 0; i < n; i++ {
+		v, lOp, err := term(i)
+		if err != nil {
+			return res, err
+		}
+		switch lOp {
+		case model.AND:
+			res = and(res, v)
+		case model.OR:
+			res = or(res, v)
+		default:
+			res = v
+		}
+	}
+	return res, nil
+}
+
+func analyseFilterCondition(
+	condition string,
+) (*expression.ConditionBuilder, error) {
+	terms, err := parseFilterCondition(condition)
 	if err != nil {
 		return nil, err
 	}
-	switch lOp {
-	case model.AND:
-		c = c.And(*_c)
-	case model.OR:
-		c = c.Or(*_c)
-	default:
-		c = *_c
+	c, err := joinFilterTerms(
+		len(terms),
+		func(i int) (expression.ConditionBuilder, model.LogicalOperator, error) {
+			t := terms[i]
+			c, err := makeExpression(t.operator, t.keyType, t.values, t.key, t.not)
+			if err != nil {
+				return expression.ConditionBuilder{}, 0, err
+			}
+			return *c, t.logicalOperator, nil
+		},
+		func(a, b expression.ConditionBuilder) expression.ConditionBuilder { return a.And(b) },
+		func(a, b expression.ConditionBuilder) expression.ConditionBuilder { return a.Or(b) },
+	)
+	if err != nil {
+		return nil, err
 	}
-
 	return &c, nil
 }
 
+func isSetType(t model.AttributeType) bool {
+	switch t.String() {
+	case new(model.SS).String(), new(model.NS).String(), new(model.BS).String():
+		return true
+	default:
+		return false
+	}
+}
+
 func makeExpressionValue(
 	op model.ComparisonOperator,
 	conditionKeyType model.AttributeType,
@@ -158,6 +212,19 @@ func makeExpressionValue(
 			expression.Value(
 				&types.AttributeValueMemberNS{Value: conditionValue},
 			)}, nil
+	case new(model.BS).String():
+		bs := make([][]byte, len(conditionValue))
+		for i := range conditionValue {
+			b, err := model.DecodeBinary(conditionValue[i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid condition, cannot convert key type: %v", err)
+			}
+			bs[i] = b
+		}
+		return []expression.OperandBuilder{
+			expression.Value(
+				&types.AttributeValueMemberBS{Value: bs},
+			)}, nil
 	default:
 		v := make([]expression.OperandBuilder, len(conditionValue))
 		for i := range conditionValue {
@@ -166,7 +233,14 @@ func makeExpressionValue(
 				return nil, fmt.Errorf("invalid condition, cannot convert key type: %v", err)
 			}
 			if op == model.CONTAINS || op == model.BeginsWith {
-				return cv, nil
+				switch x := cv.(type) {
+				case string:
+					return x, nil
+				case []byte:
+					return binaryOperandPrefix + string(x), nil
+				default:
+					return nil, fmt.Errorf("invalid condition, %s can use only type S or B", op.String())
+				}
 			}
 			v[i] = expression.Value(cv)
 		}
@@ -174,6 +248,21 @@ func makeExpressionValue(
 	}
 }
 
+// binaryOperandPrefix marks the operand of contains and begins_with which is type B,
+// because the expression builder accepts only the string for them.
+const binaryOperandPrefix = "\x00edy:B:"
+
+// expressionValues returns the values of the expression, whose operand marked by binaryOperandPrefix is type B.
+func expressionValues(expr expression.Expression) map[string]types.AttributeValue {
+	values := expr.Values()
+	for k, v := range values {
+		if s, ok := v.(*types.AttributeValueMemberS); ok && strings.HasPrefix(s.Value, binaryOperandPrefix) {
+			values[k] = &types.AttributeValueMemberB{Value: []byte(strings.TrimPrefix(s.Value, binaryOperandPrefix))}
+		}
+	}
+	return values
+}
+
 func makeExpression(
 	op model.ComparisonOperator,
 	conditionKeyType model.AttributeType,
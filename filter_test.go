@@ -81,6 +81,20 @@ func Test_analyseFilterCondition(t *testing.T) {
 			},
 			want: expression.Contains(expression.Name("ID"), "1234"),
 		},
+		{
+			name: "BeginsWith binary case",
+			args: args{
+				condition: "ID,B begins_with YWJj",
+			},
+			want: expression.BeginsWith(expression.Name("ID"), binaryOperandPrefix+"abc"),
+		},
+		{
+			name: "Contains binary case",
+			args: args{
+				condition: "ID,B contains YWJj",
+			},
+			want: expression.Contains(expression.Name("ID"), binaryOperandPrefix+"abc"),
+		},
 		{
 			name: "IN case",
 			args: args{
@@ -297,6 +311,32 @@ func Test_analyseFilterCondition(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Binary case",
+			args: args{
+				condition: "Hash,B = dGVzdA== and Hashes,BS = dGVzdDE= dGVzdDI=",
+			},
+			want: expression.Equal(expression.Name("Hash"), expression.Value([]byte("test"))).And(
+				expression.Equal(
+					expression.Name("Hashes"),
+					expression.Value(&types.AttributeValueMemberBS{Value: [][]byte{[]byte("test1"), []byte("test2")}}),
+				),
+			),
+		},
+		{
+			name: "Invalid binary value",
+			args: args{
+				condition: "Hash,B = !test",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Number cannot use begins_with",
+			args: args{
+				condition: "ID,N begins_with 12",
+			},
+			wantErr: true,
+		},
 		{
 			name: "Invalid logical operator",
 			args: args{
@@ -335,3 +375,21 @@ func Test_analyseFilterCondition(t *testing.T) {
 		})
 	}
 }
+
+func Test_expressionValues(t *testing.T) {
+	c, err := analyseFilterCondition("Data,B contains YWJj and Name,S begins_with Al")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expr, err := expression.NewBuilder().WithCondition(*c).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]types.AttributeValue{
+		":0": &types.AttributeValueMemberB{Value: []byte("abc")},
+		":1": &types.AttributeValueMemberS{Value: "Al"},
+	}
+	if got := expressionValues(expr); !reflect.DeepEqual(got, want) {
+		t.Errorf("expressionValues() = %v, want %v", got, want)
+	}
+}
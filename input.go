@@ -0,0 +1,220 @@
+package edy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type inputFormatType int
+
+const (
+	autoInputType inputFormatType = iota
+	jsonInputType
+	dynamoDBJSONInputType
+	csvInputType
+	// awsExportInputType is the directory of the table export to S3.
+	awsExportInputType
+)
+
+var inputFormatTypeMap = map[string]inputFormatType{
+	"":              autoInputType,
+	"json":          jsonInputType,
+	"jsonl":         jsonInputType,
+	"dynamodb-json": dynamoDBJSONInputType,
+	"csv":           csvInputType,
+	"aws-export":    awsExportInputType,
+}
+
+func convertToInputFormat(inputFormat string) (inputFormatType, error) {
+	if v, ok := inputFormatTypeMap[strings.ToLower(inputFormat)]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("invalid input format: %s", inputFormat)
+}
+
+// detectInputFormat resolves autoInputType by the file extension.
+// JSON and DynamoDB JSON are distinguished by jsonItemDecoder while reading.
+func detectInputFormat(format inputFormatType, fileName string) inputFormatType {
+	if format != autoInputType {
+		return format
+	}
+	if strings.EqualFold(filepath.Ext(fileName), ".csv") {
+		return csvInputType
+	}
+	return autoInputType
+}
+
+// openInput returns the reader of --item or --input-file.
+func openInput(item, fileName string, f func(string) (io.ReadCloser, error)) (io.ReadCloser, error) {
+	if len(fileName) == 0 {
+		return io.NopCloser(strings.NewReader(item)), nil
+	}
+	return f(fileName)
+}
+
+// jsonItemDecoder reads JSON objects one by one from a JSON object, JSON array of objects or JSON Lines.
+// The items of the output of aws dynamodb scan, query and get-item are also read.
+type jsonItemDecoder struct {
+	d      *json.Decoder
+	format inputFormatType
+	// inArray is true while reading the elements of the top level array.
+	inArray bool
+	// pending is the items which are unwrapped from such as {"Items":[...]}.
+	pending []interface{}
+	// count is the number of items which have been read. It is also the 1-based index of the last item.
+	count int
+	list  bool
+}
+
+func newJSONItemDecoder(r io.Reader, format inputFormatType) (*jsonItemDecoder, error) {
+	br := bufio.NewReader(r)
+	var first byte
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no item in the input")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !strings.ContainsRune(" \t\r\n", rune(b)) {
+			first = b
+			break
+		}
+	}
+	if err := br.UnreadByte(); err != nil {
+		return nil, err
+	}
+
+	d := json.NewDecoder(br)
+	d.UseNumber()
+	j := &jsonItemDecoder{
+		d:      d,
+		format: format,
+	}
+	if first == '[' {
+		if _, err := d.Token(); err != nil {
+			return nil, fmt.Errorf("invalid json format: %v", err)
+		}
+		j.inArray = true
+		j.list = true
+	}
+	return j, nil
+}
+
+// isList reports whether the input is array or has more than one item.
+func (j *jsonItemDecoder) isList() bool {
+	return j.list || j.count > 1
+}
+
+func (j *jsonItemDecoder) nextValue() (interface{}, error) {
+	if len(j.pending) != 0 {
+		v := j.pending[0]
+		j.pending = j.pending[1:]
+		return v, nil
+	}
+	if j.inArray {
+		if !j.d.More() {
+			if _, err := j.d.Token(); err != nil {
+				return nil, fmt.Errorf("invalid json format: %v", err)
+			}
+			j.inArray = false
+			return j.nextValue()
+		}
+	}
+
+	var v interface{}
+	if err := j.d.Decode(&v); err == io.EOF {
+		return nil, io.EOF
+	} else if err != nil {
+		return nil, fmt.Errorf("invalid json format, item %d: %v", j.count+1, err)
+	}
+	if j.format == jsonInputType {
+		return v, nil
+	}
+
+	u := unwrapDynamoDBJSON(v)
+	if items, ok := u.([]interface{}); ok {
+		j.list = true
+		if j.format == autoInputType {
+			j.format = dynamoDBJSONInputType
+		}
+		j.pending = items
+		return j.nextValue()
+	}
+	return u, nil
+}
+
+// next returns the raw item and whether it is DynamoDB JSON. It returns io.EOF if there is no more item.
+func (j *jsonItemDecoder) next() (map[string]interface{}, bool, error) {
+	v, err := j.nextValue()
+	if err != nil {
+		return nil, false, err
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false, fmt.Errorf("invalid json format, item %d must be object: %v", j.count+1, v)
+	}
+	j.count++
+	if j.format == autoInputType {
+		// The format is decided by the first item, and the rest of items are read in the same format.
+		j.format = jsonInputType
+		if isDynamoDBJSONItem(m) {
+			j.format = dynamoDBJSONInputType
+		}
+	}
+	return m, j.format == dynamoDBJSONInputType, nil
+}
+
+// itemReader reads the items to write one by one.
+type itemReader interface {
+	// next returns io.EOF if there is no more item.
+	next() (map[string]types.AttributeValue, error)
+	// isList reports whether the input is array or has more than one item.
+	isList() bool
+}
+
+type jsonItemReader struct {
+	*jsonItemDecoder
+	opt *attributeOption
+}
+
+func (r *jsonItemReader) next() (map[string]types.AttributeValue, error) {
+	m, typed, err := r.jsonItemDecoder.next()
+	if err != nil {
+		return nil, err
+	}
+	var item map[string]types.AttributeValue
+	if typed {
+		item, err = analyseDynamoDBJSONItem(m)
+	} else {
+		item, err = recursiveAnalyseJSON(m, "", r.opt)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("item %d: %v", r.count, err)
+	}
+	return item, nil
+}
+
+type sliceItemReader struct {
+	items []map[string]types.AttributeValue
+	i     int
+}
+
+func (r *sliceItemReader) next() (map[string]types.AttributeValue, error) {
+	if r.i >= len(r.items) {
+		return nil, io.EOF
+	}
+	r.i++
+	return r.items[r.i-1], nil
+}
+
+func (r *sliceItemReader) isList() bool {
+	return true
+}
@@ -0,0 +1,217 @@
+package edy
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_jsonItemDecoder(t *testing.T) {
+	type args struct {
+		input  string
+		format inputFormatType
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      []map[string]interface{}
+		wantTyped bool
+		wantList  bool
+		wantErr   bool
+	}{
+		{
+			name: "Parse list JSON",
+			args: args{
+				input: "[{\"TEST_KEY1\":\"TEST_VALUE1\"}," +
+					"{\"TEST_KEY2\":\"TEST_VALUE2\", \"TEST_KEY3\":[\"TEST_VALUE31\",32,true]}]",
+			},
+			want: []map[string]interface{}{
+				{
+					"TEST_KEY1": "TEST_VALUE1",
+				},
+				{
+					"TEST_KEY2": "TEST_VALUE2",
+					"TEST_KEY3": []interface{}{"TEST_VALUE31", json.Number("32"), true},
+				},
+			},
+			wantList: true,
+		},
+		{
+			name: "Parse non list JSON",
+			args: args{
+				input: "{\"TEST_KEY1\":\"TEST_VALUE1\"}",
+			},
+			want: []map[string]interface{}{
+				{
+					"TEST_KEY1": "TEST_VALUE1",
+				},
+			},
+		},
+		{
+			name: "Parse JSON Lines",
+			args: args{
+				input: "{\"TEST_KEY1\":\"TEST_VALUE1\"}\n{\"TEST_KEY1\":\"TEST_VALUE2\"}\n",
+			},
+			want: []map[string]interface{}{
+				{
+					"TEST_KEY1": "TEST_VALUE1",
+				},
+				{
+					"TEST_KEY1": "TEST_VALUE2",
+				},
+			},
+			wantList: true,
+		},
+		{
+			name: "Detect DynamoDB JSON",
+			args: args{
+				input: "{\"ID\":{\"N\":\"1\"},\"Name\":{\"S\":\"Alice\"}}",
+			},
+			want: []map[string]interface{}{
+				{
+					"ID":   map[string]interface{}{"N": "1"},
+					"Name": map[string]interface{}{"S": "Alice"},
+				},
+			},
+			wantTyped: true,
+		},
+		{
+			name: "Detect DynamoDB JSON of scan output",
+			args: args{
+				input: "{\"Items\":[{\"ID\":{\"N\":\"1\"}}],\"Count\":1}",
+			},
+			want: []map[string]interface{}{
+				{
+					"ID": map[string]interface{}{"N": "1"},
+				},
+			},
+			wantTyped: true,
+			wantList:  true,
+		},
+		{
+			name: "Plain JSON which has object attribute",
+			args: args{
+				input: "{\"ID\":1,\"Name\":{\"S\":\"Alice\"}}",
+			},
+			want: []map[string]interface{}{
+				{
+					"ID":   json.Number("1"),
+					"Name": map[string]interface{}{"S": "Alice"},
+				},
+			},
+		},
+		{
+			name: "Plain JSON which has Items attribute without Count",
+			args: args{
+				input: "{\"ID\":1,\"Items\":[{\"Name\":{\"S\":\"Alice\"}}]}",
+			},
+			want: []map[string]interface{}{
+				{
+					"ID":    json.Number("1"),
+					"Items": []interface{}{map[string]interface{}{"Name": map[string]interface{}{"S": "Alice"}}},
+				},
+			},
+		},
+		{
+			name: "Plain JSON which has object attribute of type names but different values",
+			args: args{
+				input: "{\"Size\":{\"N\":10},\"Flag\":{\"BOOL\":\"yes\"}}",
+			},
+			want: []map[string]interface{}{
+				{
+					"Size": map[string]interface{}{"N": json.Number("10")},
+					"Flag": map[string]interface{}{"BOOL": "yes"},
+				},
+			},
+		},
+		{
+			name: "DynamoDB JSON is read as plain JSON when format is json",
+			args: args{
+				input:  "{\"ID\":{\"N\":\"1\"}}",
+				format: jsonInputType,
+			},
+			want: []map[string]interface{}{
+				{
+					"ID": map[string]interface{}{"N": "1"},
+				},
+			},
+		},
+		{
+			name: "Error parse list JSON",
+			args: args{
+				input: "[{\"TEST_KEY1\"\"TEST_VALUE1\"}]",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error parse non list JSON",
+			args: args{
+				input: "{\"TEST_KEY1\"\"TEST_VALUE1\"}",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error item is not object",
+			args: args{
+				input: "[1]",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := newJSONItemDecoder(strings.NewReader(tt.args.input), tt.args.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []map[string]interface{}
+			var gotTyped bool
+			for {
+				m, typed, err := d.next()
+				if err == io.EOF {
+					break
+				}
+				if (err != nil) != tt.wantErr {
+					t.Errorf("next() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if err != nil {
+					return
+				}
+				got = append(got, m)
+				gotTyped = typed
+			}
+			if tt.wantErr {
+				t.Errorf("next() error = nil, wantErr %v", tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("next() got = %v, want %v", got, tt.want)
+			}
+			if gotTyped != tt.wantTyped {
+				t.Errorf("next() typed = %v, want %v", gotTyped, tt.wantTyped)
+			}
+			if d.isList() != tt.wantList {
+				t.Errorf("isList() = %v, want %v", d.isList(), tt.wantList)
+			}
+		})
+	}
+}
+
+// fileFixture converts the file content fixture to the file reader which Put and Delete accept.
+func fileFixture(t *testing.T, f func(string) (string, error)) func(string) (io.ReadCloser, error) {
+	t.Helper()
+
+	if f == nil {
+		return nil
+	}
+	return func(fileName string) (io.ReadCloser, error) {
+		s, err := f(fileName)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(strings.NewReader(s)), nil
+	}
+}
@@ -0,0 +1,499 @@
+package edy
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const ionVersionMarker = "$ion_1_0"
+
+// errUnsupportedIon is returned for the Ion which is valid but is not written by the table export.
+var errUnsupportedIon = errors.New("unsupported Ion construct")
+
+func unsupportedIon(construct string) error {
+	return fmt.Errorf("%w: %s", errUnsupportedIon, construct)
+}
+
+// ionDecoder reads Amazon Ion text written by the table export of DynamoDB.
+// Only the values which the export uses are supported, and they are converted to the attribute values:
+// structs, lists, strings and symbols in short quotes, ints, decimals, floats, bools, nulls and blobs.
+// The string sets, number sets and binary sets are the lists annotated with $dynamodb_SS, $dynamodb_NS
+// and $dynamodb_BS. Binary Ion, long strings, clobs, timestamps, s-expressions and comments are
+// reported as errUnsupportedIon.
+type ionDecoder struct {
+	r *bufio.Reader
+}
+
+func newIonDecoder(r io.Reader) *ionDecoder {
+	return &ionDecoder{r: bufio.NewReader(r)}
+}
+
+// decode reads the next top level value. The version marker is skipped.
+func (d *ionDecoder) decode() (types.AttributeValue, error) {
+	for {
+		if err := d.skipSpace(); err != nil {
+			return nil, err
+		}
+		b, err := d.r.Peek(len(ionVersionMarker))
+		if len(b) > 0 && b[0] == 0xe0 {
+			return nil, unsupportedIon("binary Ion")
+		}
+		if err == nil && string(b) == ionVersionMarker {
+			if _, err := d.r.Discard(len(ionVersionMarker)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return d.value()
+	}
+}
+
+func (d *ionDecoder) skipSpace() error {
+	for {
+		c, err := d.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if !strings.ContainsRune(" \t\r\n", rune(c)) {
+			return d.r.UnreadByte()
+		}
+	}
+}
+
+func (d *ionDecoder) peek() (byte, error) {
+	if err := d.skipSpace(); err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	b, err := d.r.Peek(1)
+	if err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	return b[0], nil
+}
+
+func (d *ionDecoder) expect(c byte) error {
+	got, err := d.peek()
+	if err != nil {
+		return err
+	}
+	if got != c {
+		return fmt.Errorf("invalid ion, expected %q but %q", c, got)
+	}
+	_, err = d.r.ReadByte()
+	return err
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func isIonIdentifier(c byte) bool {
+	return c == '_' || c == '$' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// token reads the identifier or the number.
+func (d *ionDecoder) token() (string, error) {
+	var sb strings.Builder
+	for {
+		c, err := d.r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if !isIonIdentifier(c) && !strings.ContainsRune("+-.", rune(c)) {
+			if err := d.r.UnreadByte(); err != nil {
+				return "", err
+			}
+			break
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String(), nil
+}
+
+// isAnnotation reads :: if it follows.
+func (d *ionDecoder) isAnnotation() (bool, error) {
+	if err := d.skipSpace(); err != nil && err != io.EOF {
+		return false, err
+	}
+	b, err := d.r.Peek(2)
+	if err != nil || string(b) != "::" {
+		return false, nil
+	}
+	_, err = d.r.Discard(2)
+	return true, err
+}
+
+func (d *ionDecoder) value() (types.AttributeValue, error) {
+	var annotation string
+	for {
+		c, err := d.peek()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case c == '{':
+			b, err := d.r.Peek(2)
+			if err == nil && string(b) == "{{" {
+				return d.blob()
+			}
+			return d.structValue()
+		case c == '[':
+			return d.list(annotation)
+		case c == '"':
+			s, err := d.quoted('"')
+			if err != nil {
+				return nil, err
+			}
+			return &types.AttributeValueMemberS{Value: s}, nil
+		case c == '\'':
+			if b, err := d.r.Peek(3); err == nil && string(b) == "'''" {
+				return nil, unsupportedIon("long string")
+			}
+			s, err := d.quoted('\'')
+			if err != nil {
+				return nil, err
+			}
+			ok, err := d.isAnnotation()
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				annotation = s
+				continue
+			}
+			return &types.AttributeValueMemberS{Value: s}, nil
+		case c == '-' || c == '+' || ('0' <= c && c <= '9'):
+			t, err := d.token()
+			if err != nil {
+				return nil, err
+			}
+			if isIonTimestamp(t) {
+				return nil, unsupportedIon("timestamp")
+			}
+			n, err := ionNumber(t)
+			if err != nil {
+				return nil, err
+			}
+			return &types.AttributeValueMemberN{Value: n}, nil
+		case isIonIdentifier(c):
+			t, err := d.token()
+			if err != nil {
+				return nil, err
+			}
+			ok, err := d.isAnnotation()
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				annotation = t
+				continue
+			}
+			switch {
+			case t == "true" || t == "false":
+				return &types.AttributeValueMemberBOOL{Value: t == "true"}, nil
+			case t == "null" || strings.HasPrefix(t, "null."):
+				return &types.AttributeValueMemberNULL{Value: true}, nil
+			case t == "nan":
+				return nil, fmt.Errorf("invalid ion, nan is not supported")
+			}
+			return &types.AttributeValueMemberS{Value: t}, nil
+		case c == '(':
+			return nil, unsupportedIon("s-expression")
+		case c == '/':
+			return nil, unsupportedIon("comment")
+		default:
+			return nil, fmt.Errorf("invalid ion, unexpected %q", c)
+		}
+	}
+}
+
+// isIonTimestamp reports whether the token is the timestamp such as 2021-06-01T or 2021T.
+func isIonTimestamp(t string) bool {
+	if len(t) < 5 || (t[4] != '-' && t[4] != 'T') {
+		return false
+	}
+	for i := 0; i < 4; i++ {
+		if t[i] < '0' || '9' < t[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ionNumber converts the int, decimal or float of Ion to the number of DynamoDB such as 103. to 103.
+func ionNumber(t string) (string, error) {
+	s := strings.ReplaceAll(t, "_", "")
+	lower := strings.ToLower(s)
+	if unsigned := strings.TrimLeft(lower, "+-"); strings.HasPrefix(unsigned, "0x") || strings.HasPrefix(unsigned, "0b") {
+		i, err := strconv.ParseInt(lower, 0, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid ion number: %s", t)
+		}
+		return strconv.FormatInt(i, 10), nil
+	}
+	s = strings.NewReplacer("d", "e", "D", "e", "E", "e").Replace(s)
+	mantissa, exponent := s, ""
+	if i := strings.Index(s, "e"); i >= 0 {
+		mantissa, exponent = s[:i], s[i:]
+	}
+	mantissa = strings.TrimSuffix(mantissa, ".")
+	if _, ok := parseNumber(mantissa + exponent); !ok {
+		return "", fmt.Errorf("invalid ion number: %s", t)
+	}
+	return mantissa + exponent, nil
+}
+
+func (d *ionDecoder) fieldName() (string, error) {
+	c, err := d.peek()
+	if err != nil {
+		return "", err
+	}
+	if c == '"' || c == '\'' {
+		return d.quoted(c)
+	}
+	t, err := d.token()
+	if err != nil {
+		return "", err
+	}
+	if len(t) == 0 {
+		return "", fmt.Errorf("invalid ion, unexpected %q", c)
+	}
+	return t, nil
+}
+
+func (d *ionDecoder) structValue() (types.AttributeValue, error) {
+	if err := d.expect('{'); err != nil {
+		return nil, err
+	}
+	m := make(map[string]types.AttributeValue)
+	for {
+		c, err := d.peek()
+		if err != nil {
+			return nil, err
+		}
+		if c == '}' {
+			_, err := d.r.ReadByte()
+			return &types.AttributeValueMemberM{Value: m}, err
+		}
+		name, err := d.fieldName()
+		if err != nil {
+			return nil, err
+		}
+		if err := d.expect(':'); err != nil {
+			return nil, err
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		m[name] = v
+		if c, err = d.peek(); err != nil {
+			return nil, err
+		}
+		if c == ',' {
+			if _, err := d.r.ReadByte(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (d *ionDecoder) list(annotation string) (types.AttributeValue, error) {
+	if err := d.expect('['); err != nil {
+		return nil, err
+	}
+	var l []types.AttributeValue
+	for {
+		c, err := d.peek()
+		if err != nil {
+			return nil, err
+		}
+		if c == ']' {
+			if _, err := d.r.ReadByte(); err != nil {
+				return nil, err
+			}
+			break
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, v)
+		if c, err = d.peek(); err != nil {
+			return nil, err
+		}
+		if c == ',' {
+			if _, err := d.r.ReadByte(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ionSet(annotation, l)
+}
+
+// ionSet converts the list to the set by the annotation.
+func ionSet(annotation string, l []types.AttributeValue) (types.AttributeValue, error) {
+	switch annotation {
+	case "$dynamodb_SS":
+		ss := make([]string, len(l))
+		for i := range l {
+			s, ok := l[i].(*types.AttributeValueMemberS)
+			if !ok {
+				return nil, fmt.Errorf("invalid ion, %s must have strings", annotation)
+			}
+			ss[i] = s.Value
+		}
+		return &types.AttributeValueMemberSS{Value: ss}, nil
+	case "$dynamodb_NS":
+		ns := make([]string, len(l))
+		for i := range l {
+			n, ok := l[i].(*types.AttributeValueMemberN)
+			if !ok {
+				return nil, fmt.Errorf("invalid ion, %s must have numbers", annotation)
+			}
+			ns[i] = n.Value
+		}
+		return &types.AttributeValueMemberNS{Value: ns}, nil
+	case "$dynamodb_BS":
+		bs := make([][]byte, len(l))
+		for i := range l {
+			b, ok := l[i].(*types.AttributeValueMemberB)
+			if !ok {
+				return nil, fmt.Errorf("invalid ion, %s must have blobs", annotation)
+			}
+			bs[i] = b.Value
+		}
+		return &types.AttributeValueMemberBS{Value: bs}, nil
+	}
+	if l == nil {
+		l = []types.AttributeValue{}
+	}
+	return &types.AttributeValueMemberL{Value: l}, nil
+}
+
+func (d *ionDecoder) blob() (types.AttributeValue, error) {
+	if _, err := d.r.Discard(2); err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	for {
+		c, err := d.r.ReadByte()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		if c == '}' {
+			if err := d.expect('}'); err != nil {
+				return nil, err
+			}
+			break
+		}
+		if c == '"' || c == '\'' {
+			return nil, unsupportedIon("clob")
+		}
+		if !strings.ContainsRune(" \t\r\n", rune(c)) {
+			sb.WriteByte(c)
+		}
+	}
+	b, err := base64.StdEncoding.DecodeString(sb.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid ion blob: %v", err)
+	}
+	return &types.AttributeValueMemberB{Value: b}, nil
+}
+
+// quoted reads the string or the symbol enclosed by q, and resolves the escapes.
+func (d *ionDecoder) quoted(q byte) (string, error) {
+	if _, err := d.r.ReadByte(); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for {
+		c, err := d.r.ReadByte()
+		if err != nil {
+			return "", unexpectedEOF(err)
+		}
+		switch c {
+		case q:
+			return sb.String(), nil
+		case '\\':
+			if err := d.escape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
+
+var ionEscapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", 'n': "\n", 'f': "\f", 'r': "\r", 'v': "\v",
+	'"': "\"", '\'': "'", '?': "?", '\\': "\\", '/': "/", '\n': "",
+}
+
+func (d *ionDecoder) escape(sb *strings.Builder) error {
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+	if s, ok := ionEscapes[c]; ok {
+		sb.WriteString(s)
+		return nil
+	}
+	var n int
+	switch c {
+	case 'x':
+		n = 2
+	case 'u':
+		n = 4
+	case 'U':
+		n = 8
+	default:
+		return fmt.Errorf("invalid ion escape: \\%c", c)
+	}
+	r, err := d.hex(n)
+	if err != nil {
+		return err
+	}
+	if utf16.IsSurrogate(r) {
+		// The surrogate pair is written as \uXXXX\uXXXX.
+		b, err := d.r.Peek(2)
+		if err == nil && string(b) == "\\u" {
+			if _, err := d.r.Discard(2); err != nil {
+				return err
+			}
+			low, err := d.hex(4)
+			if err != nil {
+				return err
+			}
+			r = utf16.DecodeRune(r, low)
+		}
+	}
+	sb.WriteRune(r)
+	return nil
+}
+
+func (d *ionDecoder) hex(n int) (rune, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	r, err := strconv.ParseUint(string(b), 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ion escape: %s", string(b))
+	}
+	return rune(r), nil
+}
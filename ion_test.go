@@ -0,0 +1,130 @@
+package edy
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func Test_ionDecoder_decode(t *testing.T) {
+	tests := []struct {
+		name    string
+		ion     string
+		want    []types.AttributeValue
+		wantErr bool
+		// wantUnsupported is true if the error is errUnsupportedIon.
+		wantUnsupported bool
+	}{
+		{
+			name: "Scalar values",
+			ion: `$ion_1_0 {Item:{S:"a\"bé",N:103.,D:1.5d-3,I:-7,T:true,F:false,Z:null,` +
+				`B:{{ aGVsbG8= }},'quoted name':"q"}}`,
+			want: []types.AttributeValue{
+				&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+					"Item": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+						"S":           &types.AttributeValueMemberS{Value: "a\"bé"},
+						"N":           &types.AttributeValueMemberN{Value: "103"},
+						"D":           &types.AttributeValueMemberN{Value: "1.5e-3"},
+						"I":           &types.AttributeValueMemberN{Value: "-7"},
+						"T":           &types.AttributeValueMemberBOOL{Value: true},
+						"F":           &types.AttributeValueMemberBOOL{Value: false},
+						"Z":           &types.AttributeValueMemberNULL{Value: true},
+						"B":           &types.AttributeValueMemberB{Value: []byte("hello")},
+						"quoted name": &types.AttributeValueMemberS{Value: "q"},
+					}},
+				}},
+			},
+		},
+		{
+			name: "Sets, list and map on each line",
+			ion: "$ion_1_0 {SS:$dynamodb_SS::[\"a\",\"b\"],NS:$dynamodb_NS::[1.,2.5]}\n" +
+				"$ion_1_0 {BS:$dynamodb_BS::[{{YQ==}}],L:[1.,\"x\",[]],M:{K:{}}}\n",
+			want: []types.AttributeValue{
+				&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+					"SS": &types.AttributeValueMemberSS{Value: []string{"a", "b"}},
+					"NS": &types.AttributeValueMemberNS{Value: []string{"1", "2.5"}},
+				}},
+				&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+					"BS": &types.AttributeValueMemberBS{Value: [][]byte{[]byte("a")}},
+					"L": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+						&types.AttributeValueMemberN{Value: "1"},
+						&types.AttributeValueMemberS{Value: "x"},
+						&types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+					}},
+					"M": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+						"K": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}},
+					}},
+				}},
+			},
+		},
+		{
+			name:    "Unterminated struct",
+			ion:     `$ion_1_0 {Item:{S:"a"}`,
+			wantErr: true,
+		},
+		{
+			name:    "String set has number",
+			ion:     `{SS:$dynamodb_SS::[1.]}`,
+			wantErr: true,
+		},
+		{
+			name:            "Long string",
+			ion:             `{Item:{S:'''long string'''}}`,
+			wantErr:         true,
+			wantUnsupported: true,
+		},
+		{
+			name:            "Clob",
+			ion:             `{Item:{C:{{"clob"}}}}`,
+			wantErr:         true,
+			wantUnsupported: true,
+		},
+		{
+			name:            "Timestamp",
+			ion:             `{Item:{T:2021-06-01T12:00:00Z}}`,
+			wantErr:         true,
+			wantUnsupported: true,
+		},
+		{
+			name:            "S-expression",
+			ion:             `{Item:{E:(a b)}}`,
+			wantErr:         true,
+			wantUnsupported: true,
+		},
+		{
+			name:            "Binary Ion",
+			ion:             "\xe0\x01\x00\xea",
+			wantErr:         true,
+			wantUnsupported: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newIonDecoder(strings.NewReader(tt.ion))
+			var got []types.AttributeValue
+			var err error
+			for {
+				var v types.AttributeValue
+				v, err = d.decode()
+				if err != nil {
+					break
+				}
+				got = append(got, v)
+			}
+			if (err != io.EOF) != tt.wantErr {
+				t.Errorf("decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if errors.Is(err, errUnsupportedIon) != tt.wantUnsupported {
+				t.Errorf("decode() error = %v, wantUnsupported %v", err, tt.wantUnsupported)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decode() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package edy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/client"
+)
+
+const defaultListParallel = 4
+
+func listTables(ctx context.Context, prefix, match string) ([]string, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	var re *regexp.Regexp
+	if len(match) != 0 {
+		var err error
+		re, err = regexp.Compile(match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --match pattern: %v", err)
+		}
+	}
+
+	var names []string
+	paginator := dynamodb.NewListTablesPaginator(cli, &dynamodb.ListTablesInput{})
+	for paginator.HasMorePages() {
+		res, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range res.TableNames {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if re != nil && !re.MatchString(name) {
+				continue
+			}
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func keyString(schema []types.KeySchemaElement, attrs []types.AttributeDefinition, keyType types.KeyType) string {
+	for _, k := range schema {
+		if k.KeyType != keyType {
+			continue
+		}
+		name := aws.ToString(k.AttributeName)
+		for _, a := range attrs {
+			if aws.ToString(a.AttributeName) == name {
+				return fmt.Sprintf("%s:%s", name, a.AttributeType)
+			}
+		}
+		return name
+	}
+	return ""
+}
+
+// tableSummary describes the table and summarizes it to a row of the list.
+func tableSummary(ctx context.Context, tableName string) (map[string]interface{}, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	res, err := cli.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	t := res.Table
+	// The billing mode summary is not returned for the table which has always been provisioned.
+	mode := types.BillingModeProvisioned
+	if t.BillingModeSummary != nil {
+		mode = t.BillingModeSummary.BillingMode
+	}
+	return map[string]interface{}{
+		"tableName":    tableName,
+		"partitionKey": keyString(t.KeySchema, t.AttributeDefinitions, types.KeyTypeHash),
+		"sortKey":      keyString(t.KeySchema, t.AttributeDefinitions, types.KeyTypeRange),
+		"itemCount":    t.ItemCount,
+		"sizeBytes":    t.TableSizeBytes,
+		"billingMode":  string(mode),
+		"status":       string(t.TableStatus),
+	}, nil
+}
+
+// describeTables summarizes the tables with up to parallel requests at the same time, keeping the order.
+func describeTables(ctx context.Context, names []string, parallel int) ([]map[string]interface{}, error) {
+	if parallel < 1 {
+		parallel = 1
+	}
+	rows := make([]map[string]interface{}, len(names))
+	errs := make([]error, len(names))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			rows[i], errs[i] = tableSummary(ctx, names[i])
+		}(i)
+	}
+	wg.Wait()
+	for i := range errs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("%s: %v", names[i], errs[i])
+		}
+	}
+	return rows, nil
+}
+
+// List shows the table names, and the summary of each table if option.Describe is true.
+func (i *Instance) List(ctx context.Context, w io.Writer, option ListOption) error {
+	cli := i.NewClient.CreateInstance()
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	names, err := listTables(ctx, option.Prefix, option.Match)
+	if err != nil {
+		return err
+	}
+
+	var rows []map[string]interface{}
+	if option.Describe {
+		parallel := option.Parallel
+		if parallel == 0 {
+			parallel = defaultListParallel
+		}
+		rows, err = describeTables(ctx, names, parallel)
+		if err != nil {
+			return err
+		}
+	} else {
+		rows = make([]map[string]interface{}, len(names))
+		for i := range names {
+			rows[i] = map[string]interface{}{"tableName": names[i]}
+		}
+	}
+
+	str, err := adjustSpecifiedFormat(option.Output, rows)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s", str)
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/mocks"
+)
+
+func TestInstance_List(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		option ListOption
+	}
+	listTables := func(t *testing.T, ctx context.Context, m *mocks.MockDynamoDBAPI) {
+		t.Helper()
+
+		m.ListTablesAPIClient.On("ListTables", ctx, &dynamodb.ListTablesInput{}).
+			Return(&dynamodb.ListTablesOutput{
+				TableNames:             []string{"dev-Order", "dev-User"},
+				LastEvaluatedTableName: aws.String("dev-User"),
+			}, nil)
+		m.ListTablesAPIClient.On("ListTables", ctx, &dynamodb.ListTablesInput{
+			ExclusiveStartTableName: aws.String("dev-User"),
+		}).Return(&dynamodb.ListTablesOutput{
+			TableNames: []string{"prod-Order", "prod-User"},
+		}, nil)
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mocking func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "List all tables across pages",
+			args: args{
+				ctx: context.Background(),
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				listTables(t, ctx, m)
+
+				return m
+			},
+			want: jsonFixture(t, []map[string]interface{}{
+				{"tableName": "dev-Order"},
+				{"tableName": "dev-User"},
+				{"tableName": "prod-Order"},
+				{"tableName": "prod-User"},
+			}),
+		},
+		{
+			name: "List tables filtered by prefix and regular expression",
+			args: args{
+				ctx: context.Background(),
+				option: ListOption{
+					Prefix: "prod-",
+					Match:  "User$",
+					Output: "jsonl",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				listTables(t, ctx, m)
+
+				return m
+			},
+			want: "{\"tableName\":\"prod-User\"}\n",
+		},
+		{
+			name: "List tables with the summary",
+			args: args{
+				ctx: context.Background(),
+				option: ListOption{
+					Prefix:   "dev-",
+					Describe: true,
+					Output:   "csv",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				listTables(t, ctx, m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("dev-Order"),
+				}).Return(&dynamodb.DescribeTableOutput{
+					Table: &types.TableDescription{
+						TableName: aws.String("dev-Order"),
+						AttributeDefinitions: []types.AttributeDefinition{
+							{AttributeName: aws.String("ID"), AttributeType: types.ScalarAttributeTypeN},
+						},
+						KeySchema: []types.KeySchemaElement{
+							{AttributeName: aws.String("ID"), KeyType: types.KeyTypeHash},
+						},
+						ItemCount:      10,
+						TableSizeBytes: 1024,
+						TableStatus:    types.TableStatusActive,
+						BillingModeSummary: &types.BillingModeSummary{
+							BillingMode: types.BillingModePayPerRequest,
+						},
+					},
+				}, nil)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("dev-User"),
+				}).Return(&dynamodb.DescribeTableOutput{
+					Table: &types.TableDescription{
+						TableName: aws.String("dev-User"),
+						AttributeDefinitions: []types.AttributeDefinition{
+							{AttributeName: aws.String("ID"), AttributeType: types.ScalarAttributeTypeS},
+							{AttributeName: aws.String("Name"), AttributeType: types.ScalarAttributeTypeS},
+						},
+						KeySchema: []types.KeySchemaElement{
+							{AttributeName: aws.String("ID"), KeyType: types.KeyTypeHash},
+							{AttributeName: aws.String("Name"), KeyType: types.KeyTypeRange},
+						},
+						TableStatus: types.TableStatusCreating,
+					},
+				}, nil)
+
+				return m
+			},
+			want: "billingMode,itemCount,partitionKey,sizeBytes,sortKey,status,tableName\n" +
+				"PAY_PER_REQUEST,10,ID:N,1024,,ACTIVE,dev-Order\n" +
+				"PROVISIONED,0,ID:S,0,Name:S,CREATING,dev-User\n",
+		},
+		{
+			name: "List tables with invalid regular expression",
+			args: args{
+				ctx:    context.Background(),
+				option: ListOption{Match: "("},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+
+				return m
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: m,
+			}
+			w := &bytes.Buffer{}
+			err := i.List(tt.args.ctx, w, tt.args.option)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got := w.String(); got != tt.want {
+				t.Errorf("List() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,437 @@
+package edy
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/model"
+)
+
+// pathElement is the element of the document path such as a.b[0].
+type pathElement struct {
+	name  string
+	index int
+	// isIndex is true if the element is the index of list.
+	isIndex bool
+}
+
+func parseDocumentPath(path string) ([]pathElement, error) {
+	var elems []pathElement
+	for _, p := range strings.Split(path, ".") {
+		name := p
+		if i := strings.Index(p, "["); i >= 0 {
+			name = p[:i]
+		}
+		if len(name) == 0 {
+			return nil, fmt.Errorf("invalid document path: %s", path)
+		}
+		elems = append(elems, pathElement{name: name})
+		for rest := p[len(name):]; len(rest) != 0; {
+			end := strings.Index(rest, "]")
+			if rest[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("invalid document path: %s", path)
+			}
+			n, err := strconv.Atoi(rest[1:end])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid document path: %s", path)
+			}
+			elems = append(elems, pathElement{index: n, isIndex: true})
+			rest = rest[end+1:]
+		}
+	}
+	return elems, nil
+}
+
+// attributeAt returns the attribute of the item at the document path.
+func attributeAt(item map[string]types.AttributeValue, path []pathElement) (types.AttributeValue, bool) {
+	var av types.AttributeValue = &types.AttributeValueMemberM{Value: item}
+	for _, e := range path {
+		switch v := av.(type) {
+		case *types.AttributeValueMemberM:
+			if e.isIndex {
+				return nil, false
+			}
+			next, ok := v.Value[e.name]
+			if !ok {
+				return nil, false
+			}
+			av = next
+		case *types.AttributeValueMemberL:
+			if !e.isIndex || e.index >= len(v.Value) {
+				return nil, false
+			}
+			av = v.Value[e.index]
+		default:
+			return nil, false
+		}
+	}
+	return av, true
+}
+
+func parseNumber(s string) (*big.Float, bool) {
+	f, _, err := big.ParseFloat(s, 10, 256, big.ToNearestEven)
+	return f, err == nil
+}
+
+// compareAttributeValue compares the numbers, the strings or the binaries. ok is false if they cannot be compared.
+func compareAttributeValue(a, b types.AttributeValue) (int, bool) {
+	switch x := a.(type) {
+	case *types.AttributeValueMemberN:
+		y, ok := b.(*types.AttributeValueMemberN)
+		if !ok {
+			return 0, false
+		}
+		fx, okx := parseNumber(x.Value)
+		fy, oky := parseNumber(y.Value)
+		if !okx || !oky {
+			return 0, false
+		}
+		return fx.Cmp(fy), true
+	case *types.AttributeValueMemberS:
+		y, ok := b.(*types.AttributeValueMemberS)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x.Value, y.Value), true
+	case *types.AttributeValueMemberB:
+		y, ok := b.(*types.AttributeValueMemberB)
+		if !ok {
+			return 0, false
+		}
+		return bytes.Compare(x.Value, y.Value), true
+	}
+	return 0, false
+}
+
+func sameElements(a, b []types.AttributeValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	used := make([]bool, len(b))
+	for i := range a {
+		found := false
+		for j := range b {
+			if !used[j] && equalAttributeValue(a[i], b[j]) {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func setElements(av types.AttributeValue) []types.AttributeValue {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberSS:
+		l := make([]types.AttributeValue, len(v.Value))
+		for i := range v.Value {
+			l[i] = &types.AttributeValueMemberS{Value: v.Value[i]}
+		}
+		return l
+	case *types.AttributeValueMemberNS:
+		l := make([]types.AttributeValue, len(v.Value))
+		for i := range v.Value {
+			l[i] = &types.AttributeValueMemberN{Value: v.Value[i]}
+		}
+		return l
+	case *types.AttributeValueMemberBS:
+		l := make([]types.AttributeValue, len(v.Value))
+		for i := range v.Value {
+			l[i] = &types.AttributeValueMemberB{Value: v.Value[i]}
+		}
+		return l
+	}
+	return nil
+}
+
+// equalAttributeValue reports whether a and b are the same. Numbers are compared by the value,
+// and sets are compared regardless of the order.
+func equalAttributeValue(a, b types.AttributeValue) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	switch x := a.(type) {
+	case *types.AttributeValueMemberN, *types.AttributeValueMemberS, *types.AttributeValueMemberB:
+		c, ok := compareAttributeValue(a, b)
+		return ok && c == 0
+	case *types.AttributeValueMemberBOOL:
+		return x.Value == b.(*types.AttributeValueMemberBOOL).Value
+	case *types.AttributeValueMemberNULL:
+		return true
+	case *types.AttributeValueMemberSS, *types.AttributeValueMemberNS, *types.AttributeValueMemberBS:
+		return sameElements(setElements(a), setElements(b))
+	case *types.AttributeValueMemberL:
+		y := b.(*types.AttributeValueMemberL)
+		if len(x.Value) != len(y.Value) {
+			return false
+		}
+		for i := range x.Value {
+			if !equalAttributeValue(x.Value[i], y.Value[i]) {
+				return false
+			}
+		}
+		return true
+	case *types.AttributeValueMemberM:
+		return equalItem(x.Value, b.(*types.AttributeValueMemberM).Value)
+	}
+	return false
+}
+
+func equalItem(a, b map[string]types.AttributeValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		v, ok := b[k]
+		if !ok || !equalAttributeValue(a[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// localFilterTerm is filterTerm whose path and values are converted to evaluate the items locally.
+type localFilterTerm struct {
+	filterTerm
+	path     []pathElement
+	operands []types.AttributeValue
+}
+
+// localFilter evaluates the filter condition against the items without DynamoDB,
+// such as the items of the exported files.
+type localFilter struct {
+	terms []localFilterTerm
+}
+
+func newLocalFilter(condition string) (*localFilter, error) {
+	f := &localFilter{}
+	if len(condition) == 0 {
+		return f, nil
+	}
+	terms, err := parseFilterCondition(condition)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range terms {
+		// The values are validated in the same way as the filter of scan.
+		if _, err := makeExpressionValue(t.operator, t.keyType, t.values); err != nil {
+			return nil, err
+		}
+		path, err := parseDocumentPath(t.key)
+		if err != nil {
+			return nil, err
+		}
+		lt := localFilterTerm{filterTerm: t, path: path}
+		if isSetType(t.keyType) {
+			av, err := setValueMember(t.keyType, t.values)
+			if err != nil {
+				return nil, err
+			}
+			lt.operands = []types.AttributeValue{av}
+		} else {
+			for _, v := range t.values {
+				av, err := t.keyType.ConvertValueMember(v)
+				if err != nil {
+					return nil, err
+				}
+				lt.operands = append(lt.operands, av)
+			}
+		}
+		f.terms = append(f.terms, lt)
+	}
+	return f, nil
+}
+
+func setValueMember(t model.AttributeType, values []string) (types.AttributeValue, error) {
+	switch t.String() {
+	case new(model.SS).String():
+		return &types.AttributeValueMemberSS{Value: values}, nil
+	case new(model.NS).String():
+		return &types.AttributeValueMemberNS{Value: values}, nil
+	default:
+		bs := make([][]byte, len(values))
+		for i := range values {
+			b, err := model.DecodeBinary(values[i])
+			if err != nil {
+				return nil, err
+			}
+			bs[i] = b
+		}
+		return &types.AttributeValueMemberBS{Value: bs}, nil
+	}
+}
+
+func (t *localFilterTerm) match(item map[string]types.AttributeValue) bool {
+	av, ok := attributeAt(item, t.path)
+	if t.operator == model.EXISTS {
+		return ok != t.not
+	}
+
+	compare := func(check func(int) bool) bool {
+		if !ok {
+			return false
+		}
+		c, comparable := compareAttributeValue(av, t.operands[0])
+		return comparable && check(c)
+	}
+	var res bool
+	switch t.operator {
+	case model.EQ:
+		res = ok && equalAttributeValue(av, t.operands[0])
+	case model.NE:
+		res = !ok || !equalAttributeValue(av, t.operands[0])
+	case model.LE:
+		res = compare(func(c int) bool { return c <= 0 })
+	case model.LT:
+		res = compare(func(c int) bool { return c < 0 })
+	case model.GE:
+		res = compare(func(c int) bool { return c >= 0 })
+	case model.GT:
+		res = compare(func(c int) bool { return c > 0 })
+	case model.BETWEEN:
+		if ok {
+			lo, okLo := compareAttributeValue(av, t.operands[0])
+			hi, okHi := compareAttributeValue(av, t.operands[1])
+			res = okLo && okHi && lo >= 0 && hi <= 0
+		}
+	case model.BeginsWith:
+		res = ok && hasPrefixValue(av, t.operands[0])
+	case model.CONTAINS:
+		res = ok && containsValue(av, t.operands[0])
+	case model.IN:
+		for _, v := range t.operands {
+			if ok && equalAttributeValue(av, v) {
+				res = true
+				break
+			}
+		}
+	}
+	return res != t.not
+}
+
+// hasPrefixValue reports whether av begins with v, which is either type S or B.
+func hasPrefixValue(av, v types.AttributeValue) bool {
+	switch x := av.(type) {
+	case *types.AttributeValueMemberS:
+		s, ok := v.(*types.AttributeValueMemberS)
+		return ok && strings.HasPrefix(x.Value, s.Value)
+	case *types.AttributeValueMemberB:
+		b, ok := v.(*types.AttributeValueMemberB)
+		return ok && bytes.HasPrefix(x.Value, b.Value)
+	}
+	return false
+}
+
+// containsValue reports whether the string or the binary has the subsequence, or the set or the list has the element.
+// The operand written as S is compared with the elements of the number set as the number
+// and with the elements of the binary set as base64.
+func containsValue(av, v types.AttributeValue) bool {
+	var elements []types.AttributeValue
+	switch x := av.(type) {
+	case *types.AttributeValueMemberS:
+		s, ok := v.(*types.AttributeValueMemberS)
+		return ok && strings.Contains(x.Value, s.Value)
+	case *types.AttributeValueMemberB:
+		b, ok := v.(*types.AttributeValueMemberB)
+		return ok && bytes.Contains(x.Value, b.Value)
+	case *types.AttributeValueMemberSS:
+		elements = setElements(x)
+	case *types.AttributeValueMemberNS:
+		elements = setElements(x)
+		if s, ok := v.(*types.AttributeValueMemberS); ok {
+			v = &types.AttributeValueMemberN{Value: s.Value}
+		}
+	case *types.AttributeValueMemberBS:
+		elements = setElements(x)
+		if s, ok := v.(*types.AttributeValueMemberS); ok {
+			b, err := model.DecodeBinary(s.Value)
+			if err != nil {
+				return false
+			}
+			v = &types.AttributeValueMemberB{Value: b}
+		}
+	case *types.AttributeValueMemberL:
+		elements = x.Value
+	}
+	for _, e := range elements {
+		if equalAttributeValue(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// match evaluates the terms from left to right in the same way as the filter of scan.
+func (f *localFilter) match(item map[string]types.AttributeValue) bool {
+	if len(f.terms) == 0 {
+		return true
+	}
+	res, _ := joinFilterTerms(
+		len(f.terms),
+		func(i int) (bool, model.LogicalOperator, error) {
+			return f.terms[i].match(item), f.terms[i].logicalOperator, nil
+		},
+		func(a, b bool) bool { return a && b },
+		func(a, b bool) bool { return a || b },
+	)
+	return res
+}
+
+// parseProjection parses the attribute names of the projection. The path of the list index is not supported.
+func parseProjection(projection string) ([][]pathElement, error) {
+	var paths [][]pathElement
+	for _, name := range splitAttributeNames(projection) {
+		path, err := parseDocumentPath(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range path {
+			if e.isIndex {
+				return nil, fmt.Errorf("the list index is not supported in the projection: %s", name)
+			}
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// projectItem returns the attributes of the item at the paths, or the item itself if there is no path.
+func projectItem(item map[string]types.AttributeValue, paths [][]pathElement) map[string]types.AttributeValue {
+	if len(paths) == 0 {
+		return item
+	}
+	res := make(map[string]types.AttributeValue)
+	for _, path := range paths {
+		dst, src := res, item
+		for i, e := range path {
+			v, ok := src[e.name]
+			if !ok {
+				break
+			}
+			if i == len(path)-1 {
+				dst[e.name] = v
+				break
+			}
+			m, ok := v.(*types.AttributeValueMemberM)
+			if !ok {
+				break
+			}
+			next, ok := dst[e.name].(*types.AttributeValueMemberM)
+			if !ok {
+				next = &types.AttributeValueMemberM{Value: make(map[string]types.AttributeValue)}
+				dst[e.name] = next
+			}
+			dst, src = next.Value, m.Value
+		}
+	}
+	return res
+}
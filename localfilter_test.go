@@ -0,0 +1,128 @@
+package edy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func Test_localFilter_match(t *testing.T) {
+	item := map[string]types.AttributeValue{
+		"ID":    &types.AttributeValueMemberN{Value: "10"},
+		"Name":  &types.AttributeValueMemberS{Value: "Alice"},
+		"Tags":  &types.AttributeValueMemberSS{Value: []string{"b", "a"}},
+		"Codes": &types.AttributeValueMemberNS{Value: []string{"1", "20"}},
+		"Data":  &types.AttributeValueMemberBS{Value: [][]byte{[]byte("abc")}},
+		"Raw":   &types.AttributeValueMemberB{Value: []byte("abcdef")},
+		"Address": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"City": &types.AttributeValueMemberS{Value: "Tokyo"},
+		}},
+		"Scores": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberN{Value: "80"},
+		}},
+	}
+	tests := []struct {
+		name      string
+		condition string
+		want      bool
+		wantErr   bool
+	}{
+		{name: "Empty", condition: "", want: true},
+		{name: "EQ number", condition: "ID,N = 10", want: true},
+		{name: "EQ different type", condition: "ID,S = 10", want: false},
+		{name: "NE missing attribute", condition: "Age,N != 10", want: true},
+		{name: "GT number", condition: "ID,N > 9", want: true},
+		{name: "LE string", condition: "Name,S <= Alica", want: false},
+		{name: "BETWEEN", condition: "ID,N between 1 10", want: true},
+		{name: "BeginsWith", condition: "Name,S begins_with Al", want: true},
+		{name: "CONTAINS string", condition: "Name,S contains lic", want: true},
+		{name: "CONTAINS set", condition: "Tags,S contains a", want: true},
+		{name: "CONTAINS number set", condition: "Codes,S contains 20.0", want: true},
+		{name: "CONTAINS number set without the element", condition: "Codes,S contains 2", want: false},
+		{name: "CONTAINS binary set", condition: "Data,S contains YWJj", want: true},
+		{name: "CONTAINS binary set with invalid base64", condition: "Data,S contains !", want: false},
+		{name: "BeginsWith binary", condition: "Raw,B begins_with YWJj", want: true},
+		{name: "BeginsWith binary without the prefix", condition: "Raw,B begins_with YmNk", want: false},
+		{name: "BeginsWith binary of string", condition: "Name,B begins_with QWw=", want: false},
+		{name: "CONTAINS binary", condition: "Raw,B contains YmNk", want: true},
+		{name: "CONTAINS binary set of binary", condition: "Data,B contains YWJj", want: true},
+		{name: "IN", condition: "Name,S in Bob Alice", want: true},
+		{name: "EQ set regardless of order", condition: "Tags,SS = a b", want: true},
+		{name: "NOT EXISTS", condition: "not Age,N exists", want: true},
+		{name: "Nested map", condition: "Address.City,S = Tokyo", want: true},
+		{name: "List index", condition: "Scores[0],N >= 80", want: true},
+		{name: "NOT", condition: "not Name,S = Alice", want: false},
+		{name: "AND then OR from left", condition: "ID,N = 1 and Name,S = Bob or Tags,SS exists", want: true},
+		{name: "OR then AND from left", condition: "Tags,SS exists or ID,N = 1 and Name,S = Bob", want: false},
+		{name: "Invalid number", condition: "ID,N = x", wantErr: true},
+		{name: "Invalid condition", condition: "ID,N =", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newLocalFilter(tt.condition)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newLocalFilter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := f.match(item); got != tt.want {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_projectItem(t *testing.T) {
+	item := map[string]types.AttributeValue{
+		"ID":   &types.AttributeValueMemberN{Value: "10"},
+		"Name": &types.AttributeValueMemberS{Value: "Alice"},
+		"Address": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"City": &types.AttributeValueMemberS{Value: "Tokyo"},
+			"Zip":  &types.AttributeValueMemberS{Value: "100-0001"},
+		}},
+	}
+	tests := []struct {
+		name       string
+		projection string
+		want       map[string]types.AttributeValue
+		wantErr    bool
+	}{
+		{
+			name: "No projection",
+			want: item,
+		},
+		{
+			name:       "Top level and nested attributes",
+			projection: "ID, Address.City, Missing",
+			want: map[string]types.AttributeValue{
+				"ID": &types.AttributeValueMemberN{Value: "10"},
+				"Address": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+					"City": &types.AttributeValueMemberS{Value: "Tokyo"},
+				}},
+			},
+		},
+		{
+			name:       "List index",
+			projection: "Scores[0]",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := parseProjection(tt.projection)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseProjection() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := projectItem(item, paths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("projectItem() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
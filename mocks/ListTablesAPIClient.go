@@ -0,0 +1,45 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ListTablesAPIClient is an autogenerated mock type for the ListTablesAPIClient type
+type ListTablesAPIClient struct {
+	mock.Mock
+}
+
+// ListTables provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ListTablesAPIClient) ListTables(_a0 context.Context, _a1 *dynamodb.ListTablesInput, _a2 ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.ListTablesOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.ListTablesInput, ...func(*dynamodb.Options)) *dynamodb.ListTablesOutput); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.ListTablesOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.ListTablesInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
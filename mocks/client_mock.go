@@ -11,9 +11,21 @@ type MockDynamoDBAPI struct {
 	DescribeTableAPIClient
 	QueryAPIClient
 	ScanAPIClient
+	ListTablesAPIClient
 	PutItemClient
 	DeleteItemClient
 	BatchWriteItemClient
+	TransactWriteItemsClient
+	TransactGetItemsClient
+	ExecuteStatementClient
+	BatchExecuteStatementClient
+	CreateTableClient
+	UpdateTimeToLiveClient
+	DeleteTableClient
+	UpdateTableClient
+	DescribeTimeToLiveClient
+	DescribeContinuousBackupsClient
+	ListTagsOfResourceClient
 }
 
 func (_m *MockDynamoDBAPI) CreateInstance() client.DynamoDB {
@@ -1,7 +1,8 @@
 package model
 
 import (
-	"bytes"
+	"encoding/base64"
+	"fmt"
 	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -10,7 +11,7 @@ import (
 type AttributeType interface {
 	String() string
 	Value(s string) (interface{}, error)
-	ConvertValueMember(s string) types.AttributeValue
+	ConvertValueMember(s string) (types.AttributeValue, error)
 }
 
 type S struct{}
@@ -18,6 +19,7 @@ type N struct{}
 type B struct{}
 type SS struct{}
 type NS struct{}
+type BS struct{}
 
 func (S) String() string {
 	return "S"
@@ -27,10 +29,10 @@ func (S) Value(s string) (interface{}, error) {
 	return s, nil
 }
 
-func (S) ConvertValueMember(s string) types.AttributeValue {
+func (S) ConvertValueMember(s string) (types.AttributeValue, error) {
 	return &types.AttributeValueMemberS{
 		Value: s,
-	}
+	}, nil
 }
 
 func (N) String() string {
@@ -45,24 +47,29 @@ func (N) Value(s string) (interface{}, error) {
 	return i, nil
 }
 
-func (N) ConvertValueMember(s string) types.AttributeValue {
+func (N) ConvertValueMember(s string) (types.AttributeValue, error) {
 	return &types.AttributeValueMemberN{
 		Value: s,
-	}
+	}, nil
 }
 
 func (B) String() string {
 	return "B"
 }
 
+// Value decodes s as base64, which is the representation of binary in edy.
 func (B) Value(s string) (interface{}, error) {
-	return bytes.NewBufferString(s).Bytes(), nil
+	return DecodeBinary(s)
 }
 
-func (B) ConvertValueMember(s string) types.AttributeValue {
-	return &types.AttributeValueMemberB{
-		Value: bytes.NewBufferString(s).Bytes(),
+func (B) ConvertValueMember(s string) (types.AttributeValue, error) {
+	b, err := DecodeBinary(s)
+	if err != nil {
+		return nil, err
 	}
+	return &types.AttributeValueMemberB{
+		Value: b,
+	}, nil
 }
 
 func (SS) String() string {
@@ -73,10 +80,10 @@ func (SS) Value(s string) (interface{}, error) {
 	return s, nil
 }
 
-func (SS) ConvertValueMember(s string) types.AttributeValue {
+func (SS) ConvertValueMember(s string) (types.AttributeValue, error) {
 	return &types.AttributeValueMemberSS{
 		Value: []string{s},
-	}
+	}, nil
 }
 
 func (NS) String() string {
@@ -91,10 +98,48 @@ func (NS) Value(s string) (interface{}, error) {
 	return i, nil
 }
 
-func (NS) ConvertValueMember(s string) types.AttributeValue {
+func (NS) ConvertValueMember(s string) (types.AttributeValue, error) {
 	return &types.AttributeValueMemberNS{
 		Value: []string{s},
+	}, nil
+}
+
+func (BS) String() string {
+	return "BS"
+}
+
+func (BS) Value(s string) (interface{}, error) {
+	return DecodeBinary(s)
+}
+
+func (BS) ConvertValueMember(s string) (types.AttributeValue, error) {
+	b, err := DecodeBinary(s)
+	if err != nil {
+		return nil, err
+	}
+	return &types.AttributeValueMemberBS{
+		Value: [][]byte{b},
+	}, nil
+}
+
+// DecodeBinary decodes base64 string. Both of the standard and URL encoding with or without padding are accepted.
+func DecodeBinary(s string) ([]byte, error) {
+	for _, enc := range []*base64.Encoding{
+		base64.StdEncoding,
+		base64.RawStdEncoding,
+		base64.URLEncoding,
+		base64.RawURLEncoding,
+	} {
+		if b, err := enc.DecodeString(s); err == nil {
+			return b, nil
+		}
 	}
+	return nil, fmt.Errorf("invalid binary, it must be base64: %s", s)
+}
+
+// EncodeBinary encodes b to the standard base64 string.
+func EncodeBinary(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
 }
 
 var (
@@ -103,6 +148,7 @@ var (
 	b  B  = struct{}{}
 	ss SS = struct{}{}
 	ns NS = struct{}{}
+	bs BS = struct{}{}
 )
 
 type AttributeTypeStr string
@@ -119,6 +165,8 @@ func (a AttributeTypeStr) Name() AttributeType {
 		return ss
 	case "NS":
 		return ns
+	case "BS":
+		return bs
 	default:
 		return s
 	}
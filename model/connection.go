@@ -2,4 +2,12 @@ package model
 
 const (
 	RetryMax int = 3
+	// BatchWriteItemMax is the maximum number of requests in a BatchWriteItem call.
+	BatchWriteItemMax int = 25
+	// TransactWriteItemsMax is the maximum number of operations in a TransactWriteItems call.
+	TransactWriteItemsMax int = 100
+	// TransactGetItemsMax is the maximum number of items in a TransactGetItems call.
+	TransactGetItemsMax int = 100
+	// BatchExecuteStatementMax is the maximum number of statements in a BatchExecuteStatement call.
+	BatchExecuteStatementMax int = 25
 )
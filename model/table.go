@@ -1,12 +1,26 @@
 package model
 
+import "time"
+
 type Table struct {
-	Arn          string                  `json:"tableArn"`
-	Name         string                  `json:"tableName"`
-	PartitionKey *Key                    `json:"partitionKey"`
-	SortKey      *Key                    `json:"sortKey,omitempty"`
-	GSI          []*GlobalSecondaryIndex `json:"gsi,omitempty"`
-	ItemCount    int64                   `json:"itemCount"`
+	Arn                   string                  `json:"tableArn"`
+	Name                  string                  `json:"tableName"`
+	Status                string                  `json:"tableStatus,omitempty"`
+	CreationDateTime      *time.Time              `json:"creationDateTime,omitempty"`
+	PartitionKey          *Key                    `json:"partitionKey"`
+	SortKey               *Key                    `json:"sortKey,omitempty"`
+	GSI                   []*GlobalSecondaryIndex `json:"gsi,omitempty"`
+	LSI                   []*LocalSecondaryIndex  `json:"lsi,omitempty"`
+	BillingMode           string                  `json:"billingMode,omitempty"`
+	TableClass            string                  `json:"tableClass,omitempty"`
+	ProvisionedThroughput *ProvisionedThroughput  `json:"provisionedThroughput,omitempty"`
+	Stream                *Stream                 `json:"stream,omitempty"`
+	TTL                   *TTL                    `json:"ttl,omitempty"`
+	SSE                   *SSE                    `json:"sse,omitempty"`
+	PointInTimeRecovery   *PointInTimeRecovery    `json:"pointInTimeRecovery,omitempty"`
+	Tags                  map[string]string       `json:"tags,omitempty"`
+	ItemCount             int64                   `json:"itemCount"`
+	SizeBytes             int64                   `json:"tableSizeBytes,omitempty"`
 }
 
 type Key struct {
@@ -16,7 +30,53 @@ type Key struct {
 }
 
 type GlobalSecondaryIndex struct {
-	Name         string `json:"indexName"`
-	PartitionKey *Key   `json:"partitionKey"`
-	SortKey      *Key   `json:"sortKey,omitempty"`
+	Name                  string                 `json:"indexName"`
+	PartitionKey          *Key                   `json:"partitionKey"`
+	SortKey               *Key                   `json:"sortKey,omitempty"`
+	Projection            *Projection            `json:"projection,omitempty"`
+	ProvisionedThroughput *ProvisionedThroughput `json:"provisionedThroughput,omitempty"`
+	Status                string                 `json:"indexStatus,omitempty"`
+}
+
+// LocalSecondaryIndex has the same partition key as the table.
+type LocalSecondaryIndex struct {
+	Name       string      `json:"indexName"`
+	SortKey    *Key        `json:"sortKey"`
+	Projection *Projection `json:"projection,omitempty"`
+}
+
+type Projection struct {
+	// Type is ALL, KEYS_ONLY or INCLUDE.
+	Type string `json:"type"`
+	// NonKeyAttributes are projected in addition to the keys if Type is INCLUDE.
+	NonKeyAttributes []string `json:"nonKeyAttributes,omitempty"`
+}
+
+type ProvisionedThroughput struct {
+	ReadCapacityUnits  int64 `json:"readCapacityUnits"`
+	WriteCapacityUnits int64 `json:"writeCapacityUnits"`
+}
+
+type Stream struct {
+	// ViewType is NEW_IMAGE, OLD_IMAGE, NEW_AND_OLD_IMAGES or KEYS_ONLY.
+	ViewType string `json:"viewType"`
+}
+
+type TTL struct {
+	// AttributeName is set only if TTL is enabled or being enabled.
+	AttributeName string `json:"attributeName,omitempty"`
+	// Status is ENABLED, ENABLING, DISABLED or DISABLING, which is ignored by create-table.
+	Status string `json:"status,omitempty"`
+}
+
+// SSE is the server-side encryption of the table.
+type SSE struct {
+	Status          string `json:"status"`
+	Type            string `json:"type,omitempty"`
+	KMSMasterKeyArn string `json:"kmsMasterKeyArn,omitempty"`
+}
+
+type PointInTimeRecovery struct {
+	// Status is ENABLED or DISABLED.
+	Status string `json:"status"`
 }
@@ -7,6 +7,10 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/hirano00o/edy/model"
 )
 
 type formatType int
@@ -14,11 +18,13 @@ type formatType int
 const (
 	jsonType formatType = iota
 	csvType
+	jsonlType
 )
 
 var formatTypeMap = map[string]formatType{
-	"json": jsonType,
-	"csv":  csvType,
+	"json":  jsonType,
+	"csv":   csvType,
+	"jsonl": jsonlType,
 }
 
 func getKeyOrder(data []map[string]interface{}) []string {
@@ -36,6 +42,22 @@ func getKeyOrder(data []map[string]interface{}) []string {
 	return order
 }
 
+// csvValue formats v to the cell of csv. Binary is shown in base64 as same as JSON.
+func csvValue(v interface{}) string {
+	switch t := v.(type) {
+	case []byte:
+		return model.EncodeBinary(t)
+	case [][]byte:
+		bs := make([]string, len(t))
+		for i := range t {
+			bs[i] = model.EncodeBinary(t[i])
+		}
+		return fmt.Sprint(bs)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 func adjustSpecifiedFormat(outputFormat string, data []map[string]interface{}) (string, error) {
 	switch formatTypeMap[strings.ToLower(outputFormat)] {
 	case csvType:
@@ -49,7 +71,7 @@ func adjustSpecifiedFormat(outputFormat string, data []map[string]interface{}) (
 		for i := range data {
 			var record []string
 			for k := range keys {
-				record = append(record, fmt.Sprint(data[i][keys[k]]))
+				record = append(record, csvValue(data[i][keys[k]]))
 			}
 			err := writer.Write(record)
 			if err != nil {
@@ -61,6 +83,17 @@ func adjustSpecifiedFormat(outputFormat string, data []map[string]interface{}) (
 			return "", err
 		}
 		return b.String(), nil
+	case jsonlType:
+		var b bytes.Buffer
+		for i := range data {
+			l, err := json.Marshal(data[i])
+			if err != nil {
+				return "", err
+			}
+			b.Write(l)
+			b.WriteString("\n")
+		}
+		return b.String(), nil
 	default:
 		b, err := json.MarshalIndent(data, "", strings.Repeat(" ", 2))
 		if err != nil {
@@ -69,3 +102,34 @@ func adjustSpecifiedFormat(outputFormat string, data []map[string]interface{}) (
 		return string(b) + "\n", nil
 	}
 }
+
+// marshalYAML formats v to YAML by the JSON tags, keeping the order of the fields.
+func marshalYAML(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	// JSON is valid YAML, so the node keeps the order. The styles of JSON are cleared to write in block style.
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return "", err
+	}
+	clearYAMLStyle(&node)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for i := range node.Content {
+		clearYAMLStyle(node.Content[i])
+	}
+}
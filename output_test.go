@@ -81,6 +81,38 @@ func Test_adjustSpecifiedFormat(t *testing.T) {
 				"TEST_ATTRIBUTE_1_VALUE_2,22,[VALUE_21 VALUE_22 VALUE_23]\n" +
 				"TEST_ATTRIBUTE_1_VALUE_3,23,[VALUE_31 VALUE_32 VALUE_33]\n",
 		},
+		{
+			name: "Output csv with binary",
+			args: args{
+				outputFormat: "csv",
+				data: []map[string]interface{}{
+					{
+						"TEST_ATTRIBUTE_1": []byte("test"),
+						"TEST_ATTRIBUTE_2": [][]byte{[]byte("test1"), []byte("test2")},
+					},
+				},
+			},
+			want: "TEST_ATTRIBUTE_1,TEST_ATTRIBUTE_2\n" +
+				"dGVzdA==,[dGVzdDE= dGVzdDI=]\n",
+		},
+		{
+			name: "Output jsonl",
+			args: args{
+				outputFormat: "jsonl",
+				data: []map[string]interface{}{
+					{
+						"TEST_ATTRIBUTE_1": "TEST_ATTRIBUTE_1_VALUE_1",
+						"TEST_ATTRIBUTE_2": 21,
+					},
+					{
+						"TEST_ATTRIBUTE_1": "TEST_ATTRIBUTE_1_VALUE_2",
+						"TEST_ATTRIBUTE_2": 22,
+					},
+				},
+			},
+			want: "{\"TEST_ATTRIBUTE_1\":\"TEST_ATTRIBUTE_1_VALUE_1\",\"TEST_ATTRIBUTE_2\":21}\n" +
+				"{\"TEST_ATTRIBUTE_1\":\"TEST_ATTRIBUTE_1_VALUE_2\",\"TEST_ATTRIBUTE_2\":22}\n",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -7,8 +7,15 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 )
 
+func splitAttributeNames(names string) []string {
+	if len(strings.TrimSpace(names)) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`,[\s]*|\s+`).Split(strings.TrimSpace(names), -1)
+}
+
 func analyseProjection(projection string) *expression.ProjectionBuilder {
-	p := regexp.MustCompile(`,[\s]*|\s+`).Split(strings.TrimSpace(projection), -1)
+	p := splitAttributeNames(projection)
 	var pj expression.ProjectionBuilder
 	for i := range p {
 		pj = expression.AddNames(pj, expression.Name(p[i]))
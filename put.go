@@ -1,16 +1,19 @@
 package edy
 
 import (
-	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
@@ -37,7 +40,7 @@ func listType(items []interface{}) int {
 			} else if typ != STRING {
 				return LIST
 			}
-		case float64:
+		case float64, json.Number:
 			if typ == NONE {
 				typ = FLOAT64
 			} else if typ != FLOAT64 {
@@ -60,10 +63,98 @@ func listType(items []interface{}) int {
 	return typ
 }
 
-func setAttrEachType(item interface{}) (types.AttributeValue, error) {
+type arrayType int
+
+const (
+	setArrayType arrayType = iota
+	listArrayType
+)
+
+var arrayTypeMap = map[string]arrayType{
+	"":     setArrayType,
+	"set":  setArrayType,
+	"list": listArrayType,
+}
+
+// attributeOption decides how JSON arrays are converted.
+// setAttrs and listAttrs are attribute names or dot separated paths of nested attributes,
+// and have priority over arrayType.
+type attributeOption struct {
+	arrayType arrayType
+	setAttrs  map[string]struct{}
+	listAttrs map[string]struct{}
+}
+
+func newAttributeOption(typ, setAttrs, listAttrs string) (*attributeOption, error) {
+	t, ok := arrayTypeMap[strings.ToLower(typ)]
+	if !ok {
+		return nil, fmt.Errorf("invalid array type: %s", typ)
+	}
+	opt := &attributeOption{
+		arrayType: t,
+		setAttrs:  make(map[string]struct{}),
+		listAttrs: make(map[string]struct{}),
+	}
+	for _, name := range splitAttributeNames(setAttrs) {
+		opt.setAttrs[name] = struct{}{}
+	}
+	for _, name := range splitAttributeNames(listAttrs) {
+		if _, ok := opt.setAttrs[name]; ok {
+			return nil, fmt.Errorf("attribute is specified as both set and list: %s", name)
+		}
+		opt.listAttrs[name] = struct{}{}
+	}
+	return opt, nil
+}
+
+func (o *attributeOption) arrayTypeOf(path string) (arrayType, bool) {
+	if _, ok := o.setAttrs[path]; ok {
+		return setArrayType, true
+	}
+	if _, ok := o.listAttrs[path]; ok {
+		return listArrayType, true
+	}
+	return o.arrayType, false
+}
+
+func listAttr(items []interface{}, path string, opt *attributeOption) (types.AttributeValue, error) {
+	m := make([]types.AttributeValue, len(items))
+	for i := range items {
+		v, err := setAttrEachType(items[i], path, opt)
+		if err != nil {
+			return nil, err
+		}
+		m[i] = v
+	}
+	return &types.AttributeValueMemberL{
+		Value: m,
+	}, nil
+}
+
+// numberString formats the number of JSON. json.Number keeps the precision of the input.
+func numberString(v interface{}) string {
+	if n, ok := v.(json.Number); ok {
+		return n.String()
+	}
+	return strconv.FormatFloat(v.(float64), 'f', -1, 64)
+}
+
+// checkSetElements returns the error if the set has the duplicate elements, which DynamoDB rejects.
+func checkSetElements(ss []string, path string) error {
+	m := make(map[string]struct{}, len(ss))
+	for i := range ss {
+		if _, ok := m[ss[i]]; ok {
+			return fmt.Errorf("set can not contain the duplicate element %s: %s", ss[i], path)
+		}
+		m[ss[i]] = struct{}{}
+	}
+	return nil
+}
+
+func setAttrEachType(item interface{}, path string, opt *attributeOption) (types.AttributeValue, error) {
 	switch t := item.(type) {
 	case map[string]interface{}:
-		mm, err := recursiveAnalyseJSON(t)
+		mm, err := recursiveAnalyseJSON(t, path, opt)
 		if err != nil {
 			return nil, err
 		}
@@ -74,9 +165,9 @@ func setAttrEachType(item interface{}) (types.AttributeValue, error) {
 		return &types.AttributeValueMemberS{
 			Value: t,
 		}, nil
-	case float64:
+	case float64, json.Number:
 		return &types.AttributeValueMemberN{
-			Value: strconv.FormatFloat(t, 'f', -1, 64),
+			Value: numberString(t),
 		}, nil
 	case bool:
 		return &types.AttributeValueMemberBOOL{
@@ -88,31 +179,37 @@ func setAttrEachType(item interface{}) (types.AttributeValue, error) {
 		}, nil
 	case []interface{}:
 		typ := listType(t)
+		arrTyp, specified := opt.arrayTypeOf(path)
+		if arrTyp == listArrayType || typ == NONE {
+			if specified && arrTyp == setArrayType {
+				return nil, fmt.Errorf("empty array can not be converted to set: %s", path)
+			}
+			return listAttr(t, path, opt)
+		}
 		switch typ {
 		case LIST, BOOL, NULL:
-			m := make([]types.AttributeValue, len(t))
-			for i := range t {
-				v, err := setAttrEachType(t[i])
-				if err != nil {
-					return nil, err
-				}
-				m[i] = v
+			if specified {
+				return nil, fmt.Errorf("array can not be converted to set, elements must be all string or number: %s", path)
 			}
-			return &types.AttributeValueMemberL{
-				Value: m,
-			}, nil
+			return listAttr(t, path, opt)
 		case STRING:
 			ss := make([]string, len(t))
 			for i := range t {
 				ss[i] = t[i].(string)
 			}
+			if err := checkSetElements(ss, path); err != nil {
+				return nil, err
+			}
 			return &types.AttributeValueMemberSS{
 				Value: ss,
 			}, nil
 		case FLOAT64:
 			ss := make([]string, len(t))
 			for i := range t {
-				ss[i] = strconv.FormatFloat(t[i].(float64), 'f', -1, 64)
+				ss[i] = numberString(t[i])
+			}
+			if err := checkSetElements(ss, path); err != nil {
+				return nil, err
 			}
 			return &types.AttributeValueMemberNS{
 				Value: ss,
@@ -125,108 +222,266 @@ func setAttrEachType(item interface{}) (types.AttributeValue, error) {
 	}
 }
 
-func recursiveAnalyseJSON(items map[string]interface{}) (map[string]types.AttributeValue, error) {
+// recursiveAnalyseJSON converts JSON object to DynamoDB attributes.
+// path is the dot separated path of items, which is empty for top level attributes.
+func recursiveAnalyseJSON(
+	items map[string]interface{},
+	path string,
+	opt *attributeOption,
+) (map[string]types.AttributeValue, error) {
 	m := make(map[string]types.AttributeValue)
 	for k := range items {
-		v, err := setAttrEachType(items[k])
+		name, hint := splitTypeHint(k)
+		p := name
+		if len(path) != 0 {
+			p = path + "." + name
+		}
+		if _, ok := m[name]; ok {
+			return nil, fmt.Errorf("duplicate attribute: %s", p)
+		}
+		var v types.AttributeValue
+		var err error
+		if hint != nil {
+			v, err = setBinaryAttr(items[k], p, hint)
+		} else {
+			v, err = setAttrEachType(items[k], p, opt)
+		}
 		if err != nil {
 			return nil, err
 		}
-		m[k] = v
+		m[name] = v
 	}
 	return m, nil
 }
 
-func parseJSON(item string) (interface{}, error) {
-	var jsonItem map[string]interface{}
-	var jsonItems []map[string]interface{}
-	var err error
-
-	if strings.HasPrefix(item, "[") {
-		err = json.Unmarshal(bytes.NewBufferString(item).Bytes(), &jsonItems)
-		if err != nil {
-			return nil, fmt.Errorf("invalid json format: %v", err)
-		}
-		return jsonItems, nil
+// splitTypeHint splits the type hint from the attribute name such as "Avatar,B".
+// JSON has no binary type, so the value of B and BS is written in base64 with the type hint.
+func splitTypeHint(k string) (string, model.AttributeType) {
+	i := strings.LastIndex(k, ",")
+	if i < 0 {
+		return k, nil
+	}
+	switch k[i+1:] {
+	case new(model.B).String(), new(model.BS).String():
+		return k[:i], model.AttributeTypeStr(k[i+1:]).Name()
+	default:
+		return k, nil
 	}
+}
 
-	err = json.Unmarshal(bytes.NewBufferString(item).Bytes(), &jsonItem)
-	if err != nil {
-		return nil, fmt.Errorf("invalid json format: %v", err)
+func setBinaryAttr(item interface{}, path string, hint model.AttributeType) (types.AttributeValue, error) {
+	switch t := item.(type) {
+	case string:
+		if hint.String() != new(model.B).String() {
+			break
+		}
+		return hint.ConvertValueMember(t)
+	case []interface{}:
+		if hint.String() != new(model.BS).String() || len(t) == 0 {
+			break
+		}
+		bs := make([][]byte, len(t))
+		ss := make([]string, len(t))
+		for i := range t {
+			s, ok := t[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid binary set, elements must be base64 string: %v", t)
+			}
+			b, err := model.DecodeBinary(s)
+			if err != nil {
+				return nil, err
+			}
+			bs[i] = b
+			// The elements are compared by the decoded bytes, because the base64 can be written differently.
+			ss[i] = base64.StdEncoding.EncodeToString(b)
+		}
+		if err := checkSetElements(ss, path); err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberBS{
+			Value: bs,
+		}, nil
 	}
-	return jsonItem, nil
+	return nil, fmt.Errorf("invalid value for type %s: %v", hint.String(), item)
 }
 
-func analysePutRequestItem(item string) (interface{}, error) {
-	var jsonItem interface{}
-	var err error
-	jsonItem, err = parseJSON(item)
+func newPutItemReader(
+	r io.Reader,
+	fileName string,
+	format inputFormatType,
+	empty emptyCellType,
+	opt *attributeOption,
+) (itemReader, error) {
+	if detectInputFormat(format, fileName) == csvInputType {
+		items, err := analyseCSV(r, empty, opt)
+		if err != nil {
+			return nil, err
+		}
+		return &sliceItemReader{items: items}, nil
+	}
+	d, err := newJSONItemDecoder(r, format)
 	if err != nil {
 		return nil, err
 	}
+	return &jsonItemReader{jsonItemDecoder: d, opt: opt}, nil
+}
 
-	switch j := jsonItem.(type) {
-	case []map[string]interface{}:
-		putItems := make([]types.WriteRequest, len(j))
-		var res map[string]types.AttributeValue
-		for i := range j {
-			res, err = recursiveAnalyseJSON(j[i])
-			if err != nil {
-				return nil, err
-			}
-			putItems[i] = types.WriteRequest{
-				PutRequest: &types.PutRequest{
-					Item: res,
-				},
-			}
+// readItems reads up to n items. It is used to check the head of input before connecting.
+func readItems(r itemReader, n int) ([]map[string]types.AttributeValue, error) {
+	var items []map[string]types.AttributeValue
+	for i := 0; i < n; i++ {
+		item, err := r.next()
+		if err == io.EOF {
+			break
 		}
-		return putItems, nil
-	case map[string]interface{}:
-		return recursiveAnalyseJSON(j)
-	default:
-		return nil, fmt.Errorf("unknown error: %v", item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
 	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no item in the input")
+	}
+	return items, nil
 }
 
-func putItems(ctx context.Context, tableName string, requestItem interface{}) (map[string]interface{}, error) {
+// putItems puts the single item by PutItem, and the others by BatchWriteItem in chunks while reading.
+// head is the items which have already been read from r.
+func putItems(
+	ctx context.Context,
+	tableName string,
+	head []map[string]types.AttributeValue,
+	r itemReader,
+) (map[string]interface{}, error) {
 	cli := ctx.Value(newClientKey).(client.DynamoDB)
 
-	if reflect.TypeOf(requestItem).Kind() == reflect.Map {
+	if len(head) == 1 && !r.isList() {
 		input := &dynamodb.PutItemInput{
 			TableName: aws.String(tableName),
-			Item:      requestItem.(map[string]types.AttributeValue),
+			Item:      head[0],
 		}
 		_, err := cli.PutItem(ctx, input)
 		if err != nil {
 			return nil, err
 		}
-	} else {
-		var res *dynamodb.BatchWriteItemOutput
-		var err error
-		var unprocessedCount int
-		input := &dynamodb.BatchWriteItemInput{
-			RequestItems: map[string][]types.WriteRequest{
-				tableName: requestItem.([]types.WriteRequest),
-			},
-		}
-		for i := 0; i < 1+model.RetryMax; i++ {
-			res, err = cli.BatchWriteItem(ctx, input)
+		return unprocessedResult(nil), nil
+	}
+
+	bw := newBatchWriter(ctx, tableName)
+	for i := range head {
+		if err := bw.add(types.WriteRequest{PutRequest: &types.PutRequest{Item: head[i]}}); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		item, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v (%d items have been written)", err, bw.written)
+		}
+		if err := bw.add(types.WriteRequest{PutRequest: &types.PutRequest{Item: item}}); err != nil {
+			return nil, err
+		}
+	}
+	unprocessed, err := bw.close()
+	if err != nil {
+		return nil, err
+	}
+	return unprocessedResult(unprocessed), nil
+}
+
+// putCondition builds the condition of PutItem from --if-not-exists and --condition.
+// putCondition joins ifNotExists and c by OR, so that the new item is put as well as the existing item
+// which matches c is replaced.
+func putCondition(table *model.Table, ifNotExists bool, c *expression.ConditionBuilder) (*expression.Expression, error) {
+	var cond expression.ConditionBuilder
+	switch {
+	case ifNotExists && c != nil:
+		cond = expression.AttributeNotExists(expression.Name(table.PartitionKey.Name)).Or(*c)
+	case ifNotExists:
+		cond = expression.AttributeNotExists(expression.Name(table.PartitionKey.Name))
+	default:
+		cond = *c
+	}
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return nil, err
+	}
+	return &expr, nil
+}
+
+// conditionalPutItems puts the items one by one by PutItem with the condition,
+// because BatchWriteItem does not support the condition.
+// The items which do not match the condition are reported instead of aborting.
+func conditionalPutItems(
+	ctx context.Context,
+	tableName string,
+	table *model.Table,
+	ifNotExists bool,
+	c *expression.ConditionBuilder,
+	head []map[string]types.AttributeValue,
+	r itemReader,
+) (map[string]interface{}, error) {
+	expr, err := putCondition(table, ifNotExists, c)
+	if err != nil {
+		return nil, err
+	}
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	written := 0
+	failed := make([]map[string]interface{}, 0)
+	put := func(item map[string]types.AttributeValue) error {
+		input := &dynamodb.PutItemInput{
+			TableName:                 aws.String(tableName),
+			Item:                      item,
+			ConditionExpression:       expr.Condition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expressionValues(*expr),
+		}
+		_, err := cli.PutItem(ctx, input)
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			key, err := keyFromItem(table, item)
 			if err != nil {
-				return nil, err
+				return err
 			}
-			unprocessedCount = len(res.UnprocessedItems[tableName])
-			if unprocessedCount == 0 {
-				break
+			var v map[string]interface{}
+			if err := attributevalue.UnmarshalMap(key, &v); err != nil {
+				return err
 			}
-			input.RequestItems = res.UnprocessedItems
+			failed = append(failed, v)
+			return nil
 		}
-		if unprocessedCount > 0 {
-			return map[string]interface{}{
-				"unprocessed": res.UnprocessedItems[tableName],
-			}, nil
+		if err != nil {
+			return fmt.Errorf("%v (%d items have been written)", err, written)
+		}
+		written++
+		return nil
+	}
+
+	for i := range head {
+		if err := put(head[i]); err != nil {
+			return nil, err
 		}
 	}
-	return map[string]interface{}{"unprocessed": []string{}}, nil
+	for {
+		item, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v (%d items have been written)", err, written)
+		}
+		if err := put(item); err != nil {
+			return nil, err
+		}
+	}
+	return map[string]interface{}{
+		"unprocessed":            []string{},
+		"conditionalCheckFailed": failed,
+	}, nil
 }
 
 func (i *Instance) Put(
@@ -235,32 +490,114 @@ func (i *Instance) Put(
 	tableName,
 	item,
 	fileName string,
-	f func(string) (string, error),
+	f func(string) (io.ReadCloser, error),
+	option PutOption,
 ) error {
+	format, err := convertToInputFormat(option.InputFormat)
+	if err != nil {
+		return err
+	}
+	opt, err := newAttributeOption(option.ArrayType, option.SetAttrs, option.ListAttrs)
+	if err != nil {
+		return err
+	}
+	empty, err := convertToEmptyCellType(option.EmptyCell)
+	if err != nil {
+		return err
+	}
+	var c *expression.ConditionBuilder
+	if len(option.Condition) != 0 {
+		c, err = analyseFilterCondition(option.Condition)
+		if err != nil {
+			return err
+		}
+	}
+	var ttl time.Duration
+	if len(option.TTLIn) != 0 {
+		ttl, err = parseTTLDuration(option.TTLIn)
+		if err != nil {
+			return err
+		}
+	}
 	switch {
 	case len(item) == 0 && len(fileName) == 0:
 		return fmt.Errorf("required either --item or --input-file option")
 	case len(item) != 0 && len(fileName) != 0:
 		return fmt.Errorf("use either --item or --input-file option")
-	case len(fileName) != 0:
-		var err error
-		item, err = f(fileName)
+	case format == awsExportInputType && len(fileName) == 0:
+		return fmt.Errorf("required --input-file option with the directory of the export")
+	}
+	if len(fileName) != 0 && !i.DryRun {
+		if err := option.confirmWrite(tableName, "put"); err != nil {
+			return err
+		}
+	}
+	var r itemReader
+	if format == awsExportInputType {
+		er, err := newAWSExportItemReader(fileName, f)
+		if err != nil {
+			return err
+		}
+		defer er.close()
+		r = er
+	} else {
+		in, err := openInput(item, fileName, f)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		r, err = newPutItemReader(in, fileName, format, empty, opt)
 		if err != nil {
 			return err
 		}
 	}
-	requestItem, err := analysePutRequestItem(item)
+	// Whether single item or not is decided by the first 2 items.
+	head, err := readItems(r, 2)
 	if err != nil {
 		return err
 	}
 
-	cli := i.NewClient.CreateInstance()
+	cli := i.createInstance(w)
 	ctx = context.WithValue(ctx, newClientKey, cli)
 
-	res, err := putItems(ctx, tableName, requestItem)
+	table, err := describeTable(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	if ttl != 0 {
+		r, err = withTTL(ctx, tableName, ttl, head, r)
+		if err != nil {
+			return err
+		}
+	}
+	// The items which do not match the key schema are reported with the index in the input.
+	// Nothing is put if there is the invalid item, unless --skip-invalid puts the valid items.
+	head, vr := validateItems(table, head, r)
+	if !option.SkipInvalid {
+		if err := vr.readAll(); err != nil {
+			return err
+		}
+		if len(vr.invalid) != 0 {
+			if err := printJSON(w, map[string]interface{}{"invalid": vr.invalid}); err != nil {
+				return err
+			}
+			return fmt.Errorf("%d items do not match the key schema of %s, no item is put", len(vr.invalid), tableName)
+		}
+	}
+
+	var res map[string]interface{}
+	if option.IfNotExists || c != nil {
+		res, err = conditionalPutItems(ctx, tableName, table, option.IfNotExists, c, head, vr)
+	} else {
+		res, err = putItems(ctx, tableName, head, vr)
+	}
 	if err != nil {
 		return err
 	}
+	if len(vr.invalid) != 0 {
+		res["invalid"] = vr.invalid
+	}
 
 	b, err := json.MarshalIndent(res, "", strings.Repeat(" ", 2))
 	if err != nil {
@@ -268,5 +605,8 @@ func (i *Instance) Put(
 	}
 	fmt.Fprintf(w, "%s\n", string(b))
 
+	if len(vr.invalid) != 0 {
+		return fmt.Errorf("%d items do not match the key schema of %s", len(vr.invalid), tableName)
+	}
 	return nil
 }
@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -16,12 +16,16 @@ import (
 )
 
 func TestInstance_Put(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	now = func() time.Time { return time.Unix(1622548800, 0) }
+
 	type args struct {
 		ctx       context.Context
 		tableName string
 		item      string
 		fileName  string
 		f         func(string) (string, error)
+		option    PutOption
 	}
 	tests := []struct {
 		name    string
@@ -35,7 +39,7 @@ func TestInstance_Put(t *testing.T) {
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item:      "{\"TEST_KEY1\":\"TEST_VALUE1\"}",
+				item:      "{\"ID\":1,\"TEST_KEY1\":\"TEST_VALUE1\"}",
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
@@ -43,9 +47,13 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				input := &dynamodb.PutItemInput{
 					TableName: aws.String("TEST"),
 					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
 						"TEST_KEY1": &types.AttributeValueMemberS{
 							Value: "TEST_VALUE1",
 						},
@@ -62,7 +70,7 @@ func TestInstance_Put(t *testing.T) {
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item:      "{\"TEST_KEY1\":\"TEST_VALUE1\",\"TEST_KEY2\":[1,2,3]}",
+				item:      "{\"ID\":1,\"TEST_KEY1\":\"TEST_VALUE1\",\"TEST_KEY2\":[1,2,3]}",
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
@@ -70,9 +78,13 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				input := &dynamodb.PutItemInput{
 					TableName: aws.String("TEST"),
 					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
 						"TEST_KEY1": &types.AttributeValueMemberS{
 							Value: "TEST_VALUE1",
 						},
@@ -92,7 +104,7 @@ func TestInstance_Put(t *testing.T) {
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item: "{\"TEST_KEY1\":\"T1\",\"TEST_KEY2\":{" +
+				item: "{\"ID\":1,\"TEST_KEY1\":\"T1\",\"TEST_KEY2\":{" +
 					"\"TEST_KEY3\":{\"TEST_KEY4\":[1,2],\"TEST_KEY5\":[\"T5\"]},\"TEST_KEY6\":{\"TEST_KEY7\":1}" +
 					"},\"TEST_KEY8\":false}",
 			},
@@ -102,9 +114,13 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				input := &dynamodb.PutItemInput{
 					TableName: aws.String("TEST"),
 					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
 						"TEST_KEY1": &types.AttributeValueMemberS{
 							Value: "T1",
 						},
@@ -145,7 +161,7 @@ func TestInstance_Put(t *testing.T) {
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item:      "{\"TEST_KEY1\":[\"T1\", true, 1]}",
+				item:      "{\"ID\":1,\"TEST_KEY1\":[\"T1\", true, 1]}",
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
@@ -153,9 +169,13 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				input := &dynamodb.PutItemInput{
 					TableName: aws.String("TEST"),
 					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
 						"TEST_KEY1": &types.AttributeValueMemberL{
 							Value: []types.AttributeValue{
 								&types.AttributeValueMemberS{
@@ -182,7 +202,7 @@ func TestInstance_Put(t *testing.T) {
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item:      "{\"TEST_KEY1\":[null, \"T1\"]}",
+				item:      "{\"ID\":1,\"TEST_KEY1\":[null, \"T1\"]}",
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
@@ -190,9 +210,13 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				input := &dynamodb.PutItemInput{
 					TableName: aws.String("TEST"),
 					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
 						"TEST_KEY1": &types.AttributeValueMemberL{
 							Value: []types.AttributeValue{
 								&types.AttributeValueMemberNULL{
@@ -216,7 +240,7 @@ func TestInstance_Put(t *testing.T) {
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item:      "{\"TEST_KEY1\":null}",
+				item:      "{\"ID\":1,\"TEST_KEY1\":null}",
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
@@ -224,9 +248,13 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				input := &dynamodb.PutItemInput{
 					TableName: aws.String("TEST"),
 					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
 						"TEST_KEY1": &types.AttributeValueMemberNULL{
 							Value: true,
 						},
@@ -243,7 +271,7 @@ func TestInstance_Put(t *testing.T) {
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item:      "[{\"TEST_KEY1\":\"TEST_VALUE1\"}]",
+				item:      "[{\"ID\":1,\"TEST_KEY1\":\"TEST_VALUE1\"}]",
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
@@ -251,12 +279,16 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				input := &dynamodb.BatchWriteItemInput{
 					RequestItems: map[string][]types.WriteRequest{
 						"TEST": {
 							{
 								PutRequest: &types.PutRequest{
 									Item: map[string]types.AttributeValue{
+										"ID": &types.AttributeValueMemberN{Value: "1"},
 										"TEST_KEY1": &types.AttributeValueMemberS{
 											Value: "TEST_VALUE1",
 										},
@@ -279,8 +311,8 @@ func TestInstance_Put(t *testing.T) {
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item: "[{\"TEST_KEY1\":\"TEST_VALUE1\"}," +
-					"{\"TEST_KEY2\":\"TEST_VALUE2\", \"TEST_KEY3\":[\"TEST_VALUE31\",32,true]}]",
+				item: "[{\"ID\":1,\"TEST_KEY1\":\"TEST_VALUE1\"}," +
+					"{\"ID\":1,\"TEST_KEY2\":\"TEST_VALUE2\", \"TEST_KEY3\":[\"TEST_VALUE31\",32,true]}]",
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
@@ -288,12 +320,16 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				input := &dynamodb.BatchWriteItemInput{
 					RequestItems: map[string][]types.WriteRequest{
 						"TEST": {
 							{
 								PutRequest: &types.PutRequest{
 									Item: map[string]types.AttributeValue{
+										"ID": &types.AttributeValueMemberN{Value: "1"},
 										"TEST_KEY1": &types.AttributeValueMemberS{
 											Value: "TEST_VALUE1",
 										},
@@ -303,6 +339,7 @@ func TestInstance_Put(t *testing.T) {
 							{
 								PutRequest: &types.PutRequest{
 									Item: map[string]types.AttributeValue{
+										"ID": &types.AttributeValueMemberN{Value: "1"},
 										"TEST_KEY2": &types.AttributeValueMemberS{
 											Value: "TEST_VALUE2",
 										},
@@ -338,7 +375,7 @@ func TestInstance_Put(t *testing.T) {
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item:      "[{\"TEST_KEY1\":\"TEST_VALUE1\"}]",
+				item:      "[{\"ID\":1,\"TEST_KEY1\":\"TEST_VALUE1\"}]",
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
@@ -346,11 +383,15 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				reqItems := map[string][]types.WriteRequest{
 					"TEST": {
 						{
 							PutRequest: &types.PutRequest{
 								Item: map[string]types.AttributeValue{
+									"ID": &types.AttributeValueMemberN{Value: "1"},
 									"TEST_KEY1": &types.AttributeValueMemberS{
 										Value: "TEST_VALUE1",
 									},
@@ -378,7 +419,7 @@ func TestInstance_Put(t *testing.T) {
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item:      "[{\"TEST_KEY1\":\"TEST_VALUE1\"}]",
+				item:      "[{\"ID\":1,\"TEST_KEY1\":\"TEST_VALUE1\"}]",
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
@@ -386,11 +427,15 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				reqItems := map[string][]types.WriteRequest{
 					"TEST": {
 						{
 							PutRequest: &types.PutRequest{
 								Item: map[string]types.AttributeValue{
+									"ID": &types.AttributeValueMemberN{Value: "1"},
 									"TEST_KEY1": &types.AttributeValueMemberS{
 										Value: "TEST_VALUE1",
 									},
@@ -414,6 +459,9 @@ func TestInstance_Put(t *testing.T) {
 				strings.Repeat(" ", 6) + "\"DeleteRequest\": null,\n" +
 				strings.Repeat(" ", 6) + "\"PutRequest\": {\n" +
 				strings.Repeat(" ", 8) + "\"Item\": {\n" +
+				strings.Repeat(" ", 10) + "\"ID\": {\n" +
+				strings.Repeat(" ", 12) + "\"Value\": \"1\"\n" +
+				strings.Repeat(" ", 10) + "},\n" +
 				strings.Repeat(" ", 10) + "\"TEST_KEY1\": {\n" +
 				strings.Repeat(" ", 12) + "\"Value\": \"TEST_VALUE1\"\n" +
 				strings.Repeat(" ", 10) + "}\n" +
@@ -430,7 +478,7 @@ func TestInstance_Put(t *testing.T) {
 				tableName: "TEST",
 				fileName:  "TEST.json",
 				f: func(s string) (string, error) {
-					return "{\"TEST_KEY1\":\"TEST_VALUE1\"}", nil
+					return "{\"ID\":1,\"TEST_KEY1\":\"TEST_VALUE1\"}", nil
 				},
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
@@ -439,9 +487,13 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				input := &dynamodb.PutItemInput{
 					TableName: aws.String("TEST"),
 					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
 						"TEST_KEY1": &types.AttributeValueMemberS{
 							Value: "TEST_VALUE1",
 						},
@@ -460,7 +512,7 @@ func TestInstance_Put(t *testing.T) {
 				tableName: "TEST",
 				fileName:  "TEST.json",
 				f: func(s string) (string, error) {
-					return "[{\"TEST_KEY1\":\"TEST_VALUE1\"},{\"TEST_KEY2\":[true]}]", nil
+					return "[{\"ID\":1,\"TEST_KEY1\":\"TEST_VALUE1\"},{\"ID\":1,\"TEST_KEY2\":[true]}]", nil
 				},
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
@@ -469,12 +521,16 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				input := &dynamodb.BatchWriteItemInput{
 					RequestItems: map[string][]types.WriteRequest{
 						"TEST": {
 							{
 								PutRequest: &types.PutRequest{
 									Item: map[string]types.AttributeValue{
+										"ID": &types.AttributeValueMemberN{Value: "1"},
 										"TEST_KEY1": &types.AttributeValueMemberS{
 											Value: "TEST_VALUE1",
 										},
@@ -484,6 +540,7 @@ func TestInstance_Put(t *testing.T) {
 							{
 								PutRequest: &types.PutRequest{
 									Item: map[string]types.AttributeValue{
+										"ID": &types.AttributeValueMemberN{Value: "1"},
 										"TEST_KEY2": &types.AttributeValueMemberL{
 											Value: []types.AttributeValue{
 												&types.AttributeValueMemberBOOL{
@@ -526,7 +583,7 @@ func TestInstance_Put(t *testing.T) {
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item:      "{\"ERROR\":\"ERROR\"}",
+				item:      "{\"ID\":1,\"ERROR\":\"ERROR\"}",
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
@@ -534,9 +591,13 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				input := &dynamodb.PutItemInput{
 					TableName: aws.String("TEST"),
 					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
 						"ERROR": &types.AttributeValueMemberS{
 							Value: "ERROR",
 						},
@@ -553,7 +614,7 @@ func TestInstance_Put(t *testing.T) {
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item:      "[{\"ERROR\":\"ERROR\"}]",
+				item:      "[{\"ID\":1,\"ERROR\":\"ERROR\"}]",
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
 				t.Helper()
@@ -561,12 +622,16 @@ func TestInstance_Put(t *testing.T) {
 				m := new(mocks.MockDynamoDBAPI)
 				ctx = context.WithValue(ctx, newClientKey, m)
 				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
 				input := &dynamodb.BatchWriteItemInput{
 					RequestItems: map[string][]types.WriteRequest{
 						"TEST": {
 							{
 								PutRequest: &types.PutRequest{
 									Item: map[string]types.AttributeValue{
+										"ID": &types.AttributeValueMemberN{Value: "1"},
 										"ERROR": &types.AttributeValueMemberS{
 											Value: "ERROR",
 										},
@@ -598,14 +663,187 @@ func TestInstance_Put(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "Error both item and file is not empty",
+			name: "Put DynamoDB JSON item",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item: "{\"ID\":{\"N\":\"1\"},\"TEST_KEY1\":{\"N\":\"12345678901234567890\"}," +
+					"\"TEST_KEY2\":{\"L\":[{\"S\":\"T2\"},{\"B\":\"dGVzdA==\"}]},\"TEST_KEY3\":{\"SS\":[\"T3\"]}}",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
+				input := &dynamodb.PutItemInput{
+					TableName: aws.String("TEST"),
+					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
+						"TEST_KEY1": &types.AttributeValueMemberN{
+							Value: "12345678901234567890",
+						},
+						"TEST_KEY2": &types.AttributeValueMemberL{
+							Value: []types.AttributeValue{
+								&types.AttributeValueMemberS{
+									Value: "T2",
+								},
+								&types.AttributeValueMemberB{
+									Value: []byte("test"),
+								},
+							},
+						},
+						"TEST_KEY3": &types.AttributeValueMemberSS{
+							Value: []string{"T3"},
+						},
+					},
+				}
+				m.PutItemClient.On("PutItem", ctx, input).Return(&dynamodb.PutItemOutput{}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"unprocessed\": []\n}\n",
+		},
+		{
+			name: "Put items of aws dynamodb scan output",
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				item:      "{\"TEST_KEY1\":\"TEST_VALUE1\"}",
 				fileName:  "TEST.json",
 				f: func(s string) (string, error) {
-					return "", nil
+					return "{\"Items\":[{\"ID\":{\"N\":\"1\"},\"TEST_KEY1\":{\"S\":\"T1\"}}],\"Count\":1,\"ScannedCount\":1}", nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
+				input := &dynamodb.BatchWriteItemInput{
+					RequestItems: map[string][]types.WriteRequest{
+						"TEST": {
+							{
+								PutRequest: &types.PutRequest{
+									Item: map[string]types.AttributeValue{
+										"ID": &types.AttributeValueMemberN{Value: "1"},
+										"TEST_KEY1": &types.AttributeValueMemberS{
+											Value: "T1",
+										},
+									},
+								},
+							},
+						},
+					},
+				}
+				m.BatchWriteItemClient.On("BatchWriteItem", ctx, input).Return(&dynamodb.BatchWriteItemOutput{
+					UnprocessedItems: map[string][]types.WriteRequest{},
+				}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"unprocessed\": []\n}\n",
+		},
+		{
+			name: "Put items of table export to S3",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				fileName:  "export",
+				f: awsExportFixture(
+					t,
+					"DYNAMODB_JSON",
+					"{\"Item\":{\"ID\":{\"N\":\"1\"},\"TEST_KEY1\":{\"S\":\"T1\"}}}\n",
+				),
+				option: PutOption{
+					InputFormat: "aws-export",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
+				input := &dynamodb.BatchWriteItemInput{
+					RequestItems: map[string][]types.WriteRequest{
+						"TEST": {
+							{
+								PutRequest: &types.PutRequest{
+									Item: map[string]types.AttributeValue{
+										"ID": &types.AttributeValueMemberN{Value: "1"},
+										"TEST_KEY1": &types.AttributeValueMemberS{
+											Value: "T1",
+										},
+									},
+								},
+							},
+						},
+					},
+				}
+				m.BatchWriteItemClient.On("BatchWriteItem", ctx, input).Return(&dynamodb.BatchWriteItemOutput{
+					UnprocessedItems: map[string][]types.WriteRequest{},
+				}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"unprocessed\": []\n}\n",
+		},
+		{
+			name: "Put plain JSON item when input format is json",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"ID\":1,\"TEST_KEY1\":{\"S\":\"T1\"}}",
+				option: PutOption{
+					InputFormat: "json",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
+				input := &dynamodb.PutItemInput{
+					TableName: aws.String("TEST"),
+					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
+						"TEST_KEY1": &types.AttributeValueMemberM{
+							Value: map[string]types.AttributeValue{
+								"S": &types.AttributeValueMemberS{
+									Value: "T1",
+								},
+							},
+						},
+					},
+				}
+				m.PutItemClient.On("PutItem", ctx, input).Return(&dynamodb.PutItemOutput{}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"unprocessed\": []\n}\n",
+		},
+		{
+			name: "Error invalid DynamoDB JSON when input format is dynamodb-json",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"TEST_KEY1\":\"T1\"}",
+				option: PutOption{
+					InputFormat: "dynamodb-json",
 				},
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
@@ -615,13 +853,13 @@ func TestInstance_Put(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "Error read file",
+			name: "Error unknown input format",
 			args: args{
 				ctx:       context.Background(),
 				tableName: "TEST",
-				fileName:  "ERROR.json",
-				f: func(s string) (string, error) {
-					return "", fmt.Errorf("cannot read file")
+				item:      "{\"TEST_KEY1\":\"T1\"}",
+				option: PutOption{
+					InputFormat: "xml",
 				},
 			},
 			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
@@ -630,86 +868,618 @@ func TestInstance_Put(t *testing.T) {
 			},
 			wantErr: true,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mock := tt.mocking(t, tt.args.ctx)
-			i := &Instance{
-				NewClient: mock,
-			}
-			w := &bytes.Buffer{}
-			err := i.Put(tt.args.ctx, w, tt.args.tableName, tt.args.item, tt.args.fileName, tt.args.f)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Put() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if gotW := w.String(); gotW != tt.wantW {
-				t.Errorf("Put() gotW = %v, want %v", gotW, tt.wantW)
-			}
-		})
-	}
-}
-
-func Test_parseJSON(t *testing.T) {
-	type args struct {
-		item string
-	}
-	tests := []struct {
-		name    string
-		args    args
-		want    interface{}
-		wantErr bool
-	}{
 		{
-			name: "Parse list JSON",
+			name: "Put array as list",
 			args: args{
-				item: "[{\"TEST_KEY1\":\"TEST_VALUE1\"}," +
-					"{\"TEST_KEY2\":\"TEST_VALUE2\", \"TEST_KEY3\":[\"TEST_VALUE31\",32,true]}]",
-			},
-			want: []map[string]interface{}{
-				{
-					"TEST_KEY1": "TEST_VALUE1",
-				},
-				{
-					"TEST_KEY2": "TEST_VALUE2",
-					"TEST_KEY3": []interface{}{"TEST_VALUE31", float64(32), true},
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"ID\":1,\"TEST_KEY1\":[\"T1\",\"T1\"],\"TEST_KEY2\":{\"TEST_KEY3\":[1,2]},\"TEST_KEY4\":[]}",
+				option: PutOption{
+					ArrayType: "list",
+					SetAttrs:  "TEST_KEY2.TEST_KEY3",
 				},
 			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
+				input := &dynamodb.PutItemInput{
+					TableName: aws.String("TEST"),
+					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
+						"TEST_KEY1": &types.AttributeValueMemberL{
+							Value: []types.AttributeValue{
+								&types.AttributeValueMemberS{
+									Value: "T1",
+								},
+								&types.AttributeValueMemberS{
+									Value: "T1",
+								},
+							},
+						},
+						"TEST_KEY2": &types.AttributeValueMemberM{
+							Value: map[string]types.AttributeValue{
+								"TEST_KEY3": &types.AttributeValueMemberNS{
+									Value: []string{"1", "2"},
+								},
+							},
+						},
+						"TEST_KEY4": &types.AttributeValueMemberL{
+							Value: []types.AttributeValue{},
+						},
+					},
+				}
+				m.PutItemClient.On("PutItem", ctx, input).Return(&dynamodb.PutItemOutput{}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"unprocessed\": []\n}\n",
 		},
 		{
-			name: "Parse non list JSON",
+			name: "Put array as set",
 			args: args{
-				item: "{\"TEST_KEY1\":\"TEST_VALUE1\"}",
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"ID\":1,\"TEST_KEY1\":[\"T1\",\"T2\"],\"TEST_KEY2\":[1,2],\"TEST_KEY3\":[]}",
+				option: PutOption{
+					ListAttrs: "TEST_KEY2",
+				},
 			},
-			want: map[string]interface{}{
-				"TEST_KEY1": "TEST_VALUE1",
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
+				input := &dynamodb.PutItemInput{
+					TableName: aws.String("TEST"),
+					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
+						"TEST_KEY1": &types.AttributeValueMemberSS{
+							Value: []string{"T1", "T2"},
+						},
+						"TEST_KEY2": &types.AttributeValueMemberL{
+							Value: []types.AttributeValue{
+								&types.AttributeValueMemberN{
+									Value: "1",
+								},
+								&types.AttributeValueMemberN{
+									Value: "2",
+								},
+							},
+						},
+						"TEST_KEY3": &types.AttributeValueMemberL{
+							Value: []types.AttributeValue{},
+						},
+					},
+				}
+				m.PutItemClient.On("PutItem", ctx, input).Return(&dynamodb.PutItemOutput{}, nil)
+
+				return m
 			},
+			wantW: "{\n  \"unprocessed\": []\n}\n",
 		},
 		{
-			name: "Error parse list JSON",
+			name: "Error duplicate elements of string set",
 			args: args{
-				item: "[{\"TEST_KEY1\"\"TEST_VALUE1\"}]",
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"ID\":1,\"TEST_KEY1\":[\"T1\",\"T2\",\"T1\"]}",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				return new(mocks.MockDynamoDBAPI)
 			},
 			wantErr: true,
 		},
 		{
-			name: "Error parse non list JSON",
+			name: "Error duplicate elements of number set",
 			args: args{
-				item: "{\"TEST_KEY1\"\"TEST_VALUE1\"}",
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"ID\":1,\"TEST_KEY1\":[1,2,1]}",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				return new(mocks.MockDynamoDBAPI)
 			},
 			wantErr: true,
 		},
-	}
+		{
+			name: "Error duplicate elements of binary set",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"ID\":1,\"TEST_KEY1,BS\":[\"YWJj\",\"YWJj\"]}",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error mixed array is specified as set",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"TEST_KEY1\":[\"T1\",1]}",
+				option: PutOption{
+					SetAttrs: "TEST_KEY1",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error unknown array type",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"TEST_KEY1\":[\"T1\"]}",
+				option: PutOption{
+					ArrayType: "tuple",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Put binary with type hint",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"ID\":1,\"TEST_KEY1,B\":\"dGVzdA==\",\"TEST_KEY2,BS\":[\"dGVzdDE=\",\"dGVzdDI=\"]}",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
+				input := &dynamodb.PutItemInput{
+					TableName: aws.String("TEST"),
+					Item: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberN{Value: "1"},
+						"TEST_KEY1": &types.AttributeValueMemberB{
+							Value: []byte("test"),
+						},
+						"TEST_KEY2": &types.AttributeValueMemberBS{
+							Value: [][]byte{[]byte("test1"), []byte("test2")},
+						},
+					},
+				}
+				m.PutItemClient.On("PutItem", ctx, input).Return(&dynamodb.PutItemOutput{}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"unprocessed\": []\n}\n",
+		},
+		{
+			name: "Error binary is not base64",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"TEST_KEY1,B\":\"!test\"}",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Put items from csv file in chunks",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				fileName:  "TEST.csv",
+				f: func(s string) (string, error) {
+					var b strings.Builder
+					b.WriteString("ID,TEST_KEY1\n")
+					for i := 0; i < 26; i++ {
+						b.WriteString(fmt.Sprintf("%d,%d\n", i, i))
+					}
+					return b.String(), nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
+				requests := make([]types.WriteRequest, 26)
+				for i := range requests {
+					requests[i] = types.WriteRequest{
+						PutRequest: &types.PutRequest{
+							Item: map[string]types.AttributeValue{
+								"ID": &types.AttributeValueMemberN{Value: fmt.Sprint(i)},
+								"TEST_KEY1": &types.AttributeValueMemberN{
+									Value: fmt.Sprint(i),
+								},
+							},
+						},
+					}
+				}
+				for _, chunk := range [][]types.WriteRequest{requests[:25], requests[25:]} {
+					m.BatchWriteItemClient.On("BatchWriteItem", ctx, &dynamodb.BatchWriteItemInput{
+						RequestItems: map[string][]types.WriteRequest{
+							"TEST": chunk,
+						},
+					}).Return(&dynamodb.BatchWriteItemOutput{
+						UnprocessedItems: map[string][]types.WriteRequest{},
+					}, nil).Once()
+				}
+
+				return m
+			},
+			wantW: "{\n  \"unprocessed\": []\n}\n",
+		},
+		{
+			name: "Error both item and file is not empty",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"ID\":1,\"TEST_KEY1\":\"TEST_VALUE1\"}",
+				fileName:  "TEST.json",
+				f: func(s string) (string, error) {
+					return "", nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error read file",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				fileName:  "ERROR.json",
+				f: func(s string) (string, error) {
+					return "", fmt.Errorf("cannot read file")
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Put items except the items which do not match the key schema with skip invalid",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "[{\"TEST_KEY1\":\"T1\"},{\"ID\":1,\"TEST_KEY1\":\"T2\"},{\"ID\":\"3\",\"TEST_KEY1\":\"T3\"}]",
+				option:    PutOption{SkipInvalid: true},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
+				input := &dynamodb.BatchWriteItemInput{
+					RequestItems: map[string][]types.WriteRequest{
+						"TEST": {
+							{
+								PutRequest: &types.PutRequest{
+									Item: map[string]types.AttributeValue{
+										"ID":        &types.AttributeValueMemberN{Value: "1"},
+										"TEST_KEY1": &types.AttributeValueMemberS{Value: "T2"},
+									},
+								},
+							},
+						},
+					},
+				}
+				m.BatchWriteItemClient.On("BatchWriteItem", ctx, input).Return(&dynamodb.BatchWriteItemOutput{
+					UnprocessedItems: map[string][]types.WriteRequest{},
+				}, nil)
+
+				return m
+			},
+			wantW: jsonFixture(t, map[string]interface{}{
+				"invalid": []*invalidItem{
+					{Index: 1, Error: "required key ID"},
+					{Index: 3, Error: "key ID must be type N, but S"},
+				},
+				"unprocessed": []string{},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "Put no item if the last item does not match the key schema",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "[{\"ID\":1,\"TEST_KEY1\":\"T1\"},{\"ID\":2,\"TEST_KEY1\":\"T2\"},{\"TEST_KEY1\":\"T3\"}]",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(putDescribeTableOutputFixture(t), nil)
+
+				return m
+			},
+			wantW: jsonFixture(t, map[string]interface{}{
+				"invalid": []*invalidItem{
+					{Index: 3, Error: "required key ID"},
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "Put data if not exists",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				fileName:  "TEST.json",
+				f: func(s string) (string, error) {
+					return "[{\"TEST_PARTITION_ATTRIBUTE\":\"T1\",\"TEST_SORT_ATTRIBUTE\":\"S1\"}," +
+						"{\"TEST_PARTITION_ATTRIBUTE\":\"T2\",\"TEST_SORT_ATTRIBUTE\":\"S2\",\"TEST_ATTRIBUTE_1\":\"A\"}]", nil
+				},
+				option: PutOption{IfNotExists: true},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+				m.PutItemClient.On("PutItem", ctx, &dynamodb.PutItemInput{
+					TableName: aws.String("TEST"),
+					Item: map[string]types.AttributeValue{
+						"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "T1"},
+						"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S1"},
+					},
+					ConditionExpression:      aws.String("attribute_not_exists (#0)"),
+					ExpressionAttributeNames: map[string]string{"#0": "TEST_PARTITION_ATTRIBUTE"},
+				}).Return(&dynamodb.PutItemOutput{}, nil)
+				m.PutItemClient.On("PutItem", ctx, &dynamodb.PutItemInput{
+					TableName: aws.String("TEST"),
+					Item: map[string]types.AttributeValue{
+						"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "T2"},
+						"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S2"},
+						"TEST_ATTRIBUTE_1":         &types.AttributeValueMemberS{Value: "A"},
+					},
+					ConditionExpression:      aws.String("attribute_not_exists (#0)"),
+					ExpressionAttributeNames: map[string]string{"#0": "TEST_PARTITION_ATTRIBUTE"},
+				}).Return(nil, &types.ConditionalCheckFailedException{})
+
+				return m
+			},
+			wantW: "{\n  \"conditionalCheckFailed\": [\n    {\n" +
+				"      \"TEST_PARTITION_ATTRIBUTE\": \"T2\",\n      \"TEST_SORT_ATTRIBUTE\": \"S2\"\n    }\n  ],\n" +
+				"  \"unprocessed\": []\n}\n",
+		},
+		{
+			name: "Put data with condition",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"TEST_PARTITION_ATTRIBUTE\":\"T1\",\"TEST_SORT_ATTRIBUTE\":\"S1\",\"TEST_ATTRIBUTE_2\":4}",
+				option:    PutOption{Condition: "TEST_ATTRIBUTE_2,N = 3"},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+				m.PutItemClient.On("PutItem", ctx, &dynamodb.PutItemInput{
+					TableName: aws.String("TEST"),
+					Item: map[string]types.AttributeValue{
+						"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "T1"},
+						"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S1"},
+						"TEST_ATTRIBUTE_2":         &types.AttributeValueMemberN{Value: "4"},
+					},
+					ConditionExpression:       aws.String("#0 = :0"),
+					ExpressionAttributeNames:  map[string]string{"#0": "TEST_ATTRIBUTE_2"},
+					ExpressionAttributeValues: map[string]types.AttributeValue{":0": &types.AttributeValueMemberN{Value: "3"}},
+				}).Return(&dynamodb.PutItemOutput{}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"conditionalCheckFailed\": [],\n  \"unprocessed\": []\n}\n",
+		},
+		{
+			name: "Put data if not exists or with condition",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"TEST_PARTITION_ATTRIBUTE\":\"T1\",\"TEST_SORT_ATTRIBUTE\":\"S1\",\"TEST_ATTRIBUTE_2\":4}",
+				option:    PutOption{IfNotExists: true, Condition: "TEST_ATTRIBUTE_2,N = 3"},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+				m.PutItemClient.On("PutItem", ctx, &dynamodb.PutItemInput{
+					TableName: aws.String("TEST"),
+					Item: map[string]types.AttributeValue{
+						"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "T1"},
+						"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S1"},
+						"TEST_ATTRIBUTE_2":         &types.AttributeValueMemberN{Value: "4"},
+					},
+					ConditionExpression: aws.String("(attribute_not_exists (#0)) OR (#1 = :0)"),
+					ExpressionAttributeNames: map[string]string{
+						"#0": "TEST_PARTITION_ATTRIBUTE",
+						"#1": "TEST_ATTRIBUTE_2",
+					},
+					ExpressionAttributeValues: map[string]types.AttributeValue{":0": &types.AttributeValueMemberN{Value: "3"}},
+				}).Return(&dynamodb.PutItemOutput{}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"conditionalCheckFailed\": [],\n  \"unprocessed\": []\n}\n",
+		},
+		{
+			name: "Put data with TTL",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"TEST_PARTITION_ATTRIBUTE\":\"T1\",\"TEST_SORT_ATTRIBUTE\":\"S1\"}",
+				option:    PutOption{TTLIn: "7d"},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+				m.DescribeTimeToLiveClient.On("DescribeTimeToLive", ctx, &dynamodb.DescribeTimeToLiveInput{
+					TableName: aws.String("TEST"),
+				}).Return(&dynamodb.DescribeTimeToLiveOutput{
+					TimeToLiveDescription: &types.TimeToLiveDescription{
+						AttributeName:    aws.String("ExpiresAt"),
+						TimeToLiveStatus: types.TimeToLiveStatusEnabled,
+					},
+				}, nil)
+				m.PutItemClient.On("PutItem", ctx, &dynamodb.PutItemInput{
+					TableName: aws.String("TEST"),
+					Item: map[string]types.AttributeValue{
+						"TEST_PARTITION_ATTRIBUTE": &types.AttributeValueMemberS{Value: "T1"},
+						"TEST_SORT_ATTRIBUTE":      &types.AttributeValueMemberS{Value: "S1"},
+						"ExpiresAt":                &types.AttributeValueMemberN{Value: "1623153600"},
+					},
+				}).Return(&dynamodb.PutItemOutput{}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"unprocessed\": []\n}\n",
+		},
+		{
+			name: "Error TTL is not enabled",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"TEST_PARTITION_ATTRIBUTE\":\"T1\",\"TEST_SORT_ATTRIBUTE\":\"S1\"}",
+				option:    PutOption{TTLIn: "7d"},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTableOutputFixture(t, false), nil)
+				m.DescribeTimeToLiveClient.On("DescribeTimeToLive", ctx, &dynamodb.DescribeTimeToLiveInput{
+					TableName: aws.String("TEST"),
+				}).Return(&dynamodb.DescribeTimeToLiveOutput{
+					TimeToLiveDescription: &types.TimeToLiveDescription{TimeToLiveStatus: types.TimeToLiveStatusDisabled},
+				}, nil)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error invalid condition",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				item:      "{\"TEST_PARTITION_ATTRIBUTE\":\"T1\"}",
+				option:    PutOption{Condition: "TEST_ATTRIBUTE_2 = 3"},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseJSON(tt.args.item)
+			mock := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: mock,
+			}
+			w := &bytes.Buffer{}
+			err := i.Put(
+				tt.args.ctx,
+				w,
+				tt.args.tableName,
+				tt.args.item,
+				tt.args.fileName,
+				fileFixture(t, tt.args.f),
+				tt.args.option,
+			)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("parseJSON() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Put() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("parseJSON() got = %v, want %v", got, tt.want)
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("Put() gotW = %v, want %v", gotW, tt.wantW)
 			}
 		})
 	}
 }
+
+// putDescribeTableOutputFixture is the table whose partition key is ID of type N.
+func putDescribeTableOutputFixture(t *testing.T) *dynamodb.DescribeTableOutput {
+	t.Helper()
+
+	return &dynamodb.DescribeTableOutput{
+		Table: &types.TableDescription{
+			TableName: aws.String("TEST"),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("ID"),
+					AttributeType: types.ScalarAttributeTypeN,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("ID"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+		},
+	}
+}
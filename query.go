@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/hirano00o/edy/client"
 	"github.com/hirano00o/edy/model"
@@ -55,7 +56,11 @@ func analyseSortCondition(
 	case model.GT:
 		c = expression.KeyGreaterThan(expression.Key(sortKey), expression.Value(v1))
 	case model.BeginsWith:
-		c = expression.KeyBeginsWith(expression.Key(sortKey), v1.(string))
+		prefix, ok := v1.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid condition, begins_with can use only type S: %s", sortCondition)
+		}
+		c = expression.KeyBeginsWith(expression.Key(sortKey), prefix)
 	case model.BETWEEN:
 		v2, err := sortKeyType.Value(s[2])
 		if err != nil {
@@ -69,21 +74,15 @@ func analyseSortCondition(
 	return &c, nil
 }
 
-func query(
-	ctx context.Context,
+func queryInput(
+	table *model.Table,
 	tableName,
 	partitionValue,
 	sortCondition,
 	filterCondition,
 	index,
 	projection string,
-) ([]map[string]interface{}, error) {
-	table, err := describeTable(ctx, tableName)
-	if err != nil {
-		return nil, err
-	}
-	cli := ctx.Value(newClientKey).(client.DynamoDB)
-
+) (*dynamodb.QueryInput, error) {
 	partitionKeyName, partitionKeyType := table.PartitionKey.Name, table.PartitionKey.Type
 	var sortKeyName string
 	var sortKeyType model.AttributeType
@@ -146,7 +145,7 @@ func query(
 	input := &dynamodb.QueryInput{
 		TableName:                 aws.String(tableName),
 		ExpressionAttributeNames:  expr.Names(),
-		ExpressionAttributeValues: expr.Values(),
+		ExpressionAttributeValues: expressionValues(expr),
 		KeyConditionExpression:    expr.KeyCondition(),
 		FilterExpression:          expr.Condition(),
 		ProjectionExpression:      expr.Projection(),
@@ -154,20 +153,51 @@ func query(
 	if len(index) != 0 {
 		input.IndexName = aws.String(index)
 	}
+	return input, nil
+}
 
-	resMap := make([]map[string]interface{}, 0, table.ItemCount)
+// queryItems returns all pages of the query result.
+func queryItems(ctx context.Context, input *dynamodb.QueryInput) ([]map[string]types.AttributeValue, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	var items []map[string]types.AttributeValue
 	paginator := dynamodb.NewQueryPaginator(cli, input)
 	for paginator.HasMorePages() {
 		res, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, err
 		}
-		v := make([]map[string]interface{}, 0, 25)
-		err = attributevalue.UnmarshalListOfMaps(res.Items, &v)
-		if err != nil {
-			return nil, err
-		}
-		resMap = append(resMap, v...)
+		items = append(items, res.Items...)
+	}
+	return items, nil
+}
+
+func query(
+	ctx context.Context,
+	tableName,
+	partitionValue,
+	sortCondition,
+	filterCondition,
+	index,
+	projection string,
+) ([]map[string]interface{}, error) {
+	table, err := describeTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	input, err := queryInput(table, tableName, partitionValue, sortCondition, filterCondition, index, projection)
+	if err != nil {
+		return nil, err
+	}
+	items, err := queryItems(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	resMap := make([]map[string]interface{}, 0, len(items))
+	err = attributevalue.UnmarshalListOfMaps(items, &resMap)
+	if err != nil {
+		return nil, err
 	}
 
 	return resMap, nil
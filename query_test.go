@@ -138,6 +138,24 @@ func Test_analyseSortCondition(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Binary value",
+			args: args{
+				sortCondition: "= dGVzdA==",
+				sortKey:       "Hash",
+				sortKeyType:   model.B{},
+			},
+			want: expression.KeyEqual(expression.Key("Hash"), expression.Value([]byte("test"))),
+		},
+		{
+			name: "Binary cannot use begins_with",
+			args: args{
+				sortCondition: "begins_with dGVzdA==",
+				sortKey:       "Hash",
+				sortKeyType:   model.B{},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Invalid number value when between",
 			args: args{
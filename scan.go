@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"io"
 
 	"github.com/hirano00o/edy/client"
@@ -30,13 +31,7 @@ func (i *Instance) Scan(ctx context.Context, w io.Writer, tableName, filterCondi
 	return nil
 }
 
-func scan(ctx context.Context, tableName, filterCondition, projection string) ([]map[string]interface{}, error) {
-	table, err := describeTable(ctx, tableName)
-	if err != nil {
-		return nil, err
-	}
-	cli := ctx.Value(newClientKey).(client.DynamoDB)
-
+func scanInput(tableName, filterCondition, projection string) (*dynamodb.ScanInput, error) {
 	input := &dynamodb.ScanInput{
 		TableName: aws.String(tableName),
 	}
@@ -64,24 +59,47 @@ func scan(ctx context.Context, tableName, filterCondition, projection string) ([
 			return nil, err
 		}
 		input.ExpressionAttributeNames = expr.Names()
-		input.ExpressionAttributeValues = expr.Values()
+		input.ExpressionAttributeValues = expressionValues(expr)
 		input.FilterExpression = expr.Condition()
 		input.ProjectionExpression = expr.Projection()
 	}
+	return input, nil
+}
 
-	resMap := make([]map[string]interface{}, 0, table.ItemCount)
+// scanItems returns all pages of the scan result.
+func scanItems(ctx context.Context, input *dynamodb.ScanInput) ([]map[string]types.AttributeValue, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	var items []map[string]types.AttributeValue
 	paginator := dynamodb.NewScanPaginator(cli, input)
 	for paginator.HasMorePages() {
 		res, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, err
 		}
-		v := make([]map[string]interface{}, 0, 25)
-		err = attributevalue.UnmarshalListOfMaps(res.Items, &v)
-		if err != nil {
-			return nil, err
-		}
-		resMap = append(resMap, v...)
+		items = append(items, res.Items...)
+	}
+	return items, nil
+}
+
+func scan(ctx context.Context, tableName, filterCondition, projection string) ([]map[string]interface{}, error) {
+	table, err := describeTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	input, err := scanInput(tableName, filterCondition, projection)
+	if err != nil {
+		return nil, err
+	}
+	items, err := scanItems(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	resMap := make([]map[string]interface{}, 0, table.ItemCount)
+	err = attributevalue.UnmarshalListOfMaps(items, &resMap)
+	if err != nil {
+		return nil, err
 	}
 
 	return resMap, nil
@@ -0,0 +1,329 @@
+package edy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/client"
+	"github.com/hirano00o/edy/model"
+)
+
+// sqlStatement is the PartiQL statement read from the file.
+type sqlStatement struct {
+	Statement  string        `json:"statement"`
+	Parameters []interface{} `json:"parameters"`
+}
+
+// sqlParameters converts the parameters of the statement, which are plain JSON or DynamoDB JSON values.
+func sqlParameters(params []interface{}) ([]types.AttributeValue, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+	opt, err := newAttributeOption("", "", "")
+	if err != nil {
+		return nil, err
+	}
+	avs := make([]types.AttributeValue, len(params))
+	for i := range params {
+		var err error
+		if hasDynamoDBJSONShape(params[i]) {
+			avs[i], err = analyseDynamoDBJSONValue(params[i])
+		} else {
+			avs[i], err = setAttrEachType(params[i], "", opt)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parameter %d: %v", i+1, err)
+		}
+	}
+	return avs, nil
+}
+
+// parseSQLParameters parses JSON array of the parameters such as [1, "Alice"].
+func parseSQLParameters(params string) ([]interface{}, error) {
+	if len(strings.TrimSpace(params)) == 0 {
+		return nil, nil
+	}
+	d := json.NewDecoder(strings.NewReader(params))
+	d.UseNumber()
+	var v []interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid parameters, it must be JSON array: %v", err)
+	}
+	return v, nil
+}
+
+// readSQLStatements reads JSON array of the statements or JSON Lines.
+func readSQLStatements(r io.Reader) ([]*sqlStatement, error) {
+	raws, err := readJSONObjects(r)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(raws) == 0:
+		return nil, fmt.Errorf("no statement in the input")
+	case len(raws) > model.BatchExecuteStatementMax:
+		return nil, fmt.Errorf("too many statements, up to %d: %d", model.BatchExecuteStatementMax, len(raws))
+	}
+	stmts := make([]*sqlStatement, len(raws))
+	for i := range raws {
+		stmts[i] = new(sqlStatement)
+		if err := decodeJSONObject(raws[i], stmts[i]); err != nil {
+			return nil, fmt.Errorf("invalid json format, statement %d: %v", i+1, err)
+		}
+		if len(strings.TrimSpace(stmts[i].Statement)) == 0 {
+			return nil, fmt.Errorf("statement %d: required statement", i+1)
+		}
+	}
+	return stmts, nil
+}
+
+// executeStatement returns all pages of the result.
+func executeStatement(
+	ctx context.Context,
+	input *dynamodb.ExecuteStatementInput,
+) ([]map[string]types.AttributeValue, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	var items []map[string]types.AttributeValue
+	for {
+		res, err := cli.ExecuteStatement(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, res.Items...)
+		if res.NextToken == nil {
+			return items, nil
+		}
+		input.NextToken = res.NextToken
+	}
+}
+
+// batchExecuteStatement returns the items of the statements which succeed,
+// and the errors of the statements which fail.
+func batchExecuteStatement(
+	ctx context.Context,
+	input *dynamodb.BatchExecuteStatementInput,
+) (items []map[string]types.AttributeValue, failed []string, err error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	res, err := cli.BatchExecuteStatement(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, r := range res.Responses {
+		if r.Error != nil {
+			msg := fmt.Sprintf("statement %d: %s: %s", i+1, r.Error.Code, aws.ToString(r.Error.Message))
+			failed = append(failed, msg)
+			continue
+		}
+		if len(r.Item) != 0 {
+			items = append(items, r.Item)
+		}
+	}
+	return items, failed, nil
+}
+
+var errSQLWriteTable = errors.New("cannot find the table which the statement writes into")
+
+// sqlWriteTable returns the table which the statement such as INSERT, UPDATE and DELETE writes into.
+// The table name is empty for SELECT, which does not write.
+func sqlWriteTable(statement string) (string, error) {
+	keyword, s := sqlNextWord(statement)
+	switch strings.ToUpper(keyword) {
+	case "SELECT":
+		return "", nil
+	case "INSERT":
+		keyword, s = sqlNextWord(s)
+		if !strings.EqualFold(keyword, "INTO") {
+			return "", errSQLWriteTable
+		}
+	case "DELETE":
+		keyword, s = sqlNextWord(s)
+		if !strings.EqualFold(keyword, "FROM") {
+			return "", errSQLWriteTable
+		}
+	case "UPDATE":
+	default:
+		return "", errSQLWriteTable
+	}
+
+	if !strings.HasPrefix(s, "\"") {
+		table, _ := sqlNextWord(s)
+		if len(table) == 0 {
+			return "", errSQLWriteTable
+		}
+		return table, nil
+	}
+	// The double quote in the quoted name is escaped by doubling it.
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] != '"':
+			sb.WriteByte(s[i])
+		case i+1 < len(s) && s[i+1] == '"':
+			sb.WriteByte('"')
+			i++
+		default:
+			return sb.String(), nil
+		}
+	}
+	return "", errSQLWriteTable
+}
+
+// sqlNextWord returns the word which is separated by the space, and the rest of the statement.
+func sqlNextWord(s string) (string, string) {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	i := strings.IndexFunc(s, unicode.IsSpace)
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimLeftFunc(s[i:], unicode.IsSpace)
+}
+
+// sqlWriteTables returns the distinct tables which the statements of the input write into.
+func sqlWriteTables(input interface{}) ([]string, error) {
+	var tables []string
+	add := func(table string) {
+		for _, t := range tables {
+			if t == table {
+				return
+			}
+		}
+		tables = append(tables, table)
+	}
+	switch in := input.(type) {
+	case *dynamodb.ExecuteStatementInput:
+		table, err := sqlWriteTable(aws.ToString(in.Statement))
+		if err != nil {
+			return nil, err
+		}
+		if len(table) != 0 {
+			add(table)
+		}
+	case *dynamodb.BatchExecuteStatementInput:
+		for i := range in.Statements {
+			table, err := sqlWriteTable(aws.ToString(in.Statements[i].Statement))
+			if err != nil {
+				return nil, fmt.Errorf("statement %d: %v", i+1, err)
+			}
+			if len(table) != 0 {
+				add(table)
+			}
+		}
+	}
+	return tables, nil
+}
+
+func sqlInput(statement, parameters, fileName string, f func(string) (io.ReadCloser, error)) (interface{}, error) {
+	if len(fileName) == 0 {
+		params, err := parseSQLParameters(parameters)
+		if err != nil {
+			return nil, err
+		}
+		avs, err := sqlParameters(params)
+		if err != nil {
+			return nil, err
+		}
+		return &dynamodb.ExecuteStatementInput{
+			Statement:  aws.String(statement),
+			Parameters: avs,
+		}, nil
+	}
+
+	in, err := f(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	stmts, err := readSQLStatements(in)
+	if err != nil {
+		return nil, err
+	}
+	input := &dynamodb.BatchExecuteStatementInput{
+		Statements: make([]types.BatchStatementRequest, len(stmts)),
+	}
+	for i := range stmts {
+		avs, err := sqlParameters(stmts[i].Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %v", i+1, err)
+		}
+		input.Statements[i] = types.BatchStatementRequest{
+			Statement:  aws.String(stmts[i].Statement),
+			Parameters: avs,
+		}
+	}
+	return input, nil
+}
+
+func (i *Instance) SQL(
+	ctx context.Context,
+	w io.Writer,
+	statement,
+	fileName string,
+	f func(string) (io.ReadCloser, error),
+	option SQLOption,
+) error {
+	switch {
+	case len(statement) == 0 && len(fileName) == 0:
+		return fmt.Errorf("required the statement or --input-file option")
+	case len(statement) != 0 && len(fileName) != 0:
+		return fmt.Errorf("use either the statement or --input-file option")
+	case len(fileName) != 0 && len(option.Parameters) != 0:
+		return fmt.Errorf("--parameters can not be used with --input-file, write them in the file")
+	}
+	input, err := sqlInput(statement, option.Parameters, fileName, f)
+	if err != nil {
+		return err
+	}
+	if !i.DryRun {
+		tables, err := sqlWriteTables(input)
+		if err != nil {
+			return err
+		}
+		for _, table := range tables {
+			if err := option.confirmWrite(table, "written"); err != nil {
+				return err
+			}
+		}
+	}
+
+	cli := i.createInstance(w)
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	var items []map[string]types.AttributeValue
+	var failed []string
+	switch in := input.(type) {
+	case *dynamodb.ExecuteStatementInput:
+		items, err = executeStatement(ctx, in)
+	case *dynamodb.BatchExecuteStatementInput:
+		items, failed, err = batchExecuteStatement(ctx, in)
+	}
+	if err != nil {
+		return err
+	}
+
+	res := make([]map[string]interface{}, 0, len(items))
+	if err := attributevalue.UnmarshalListOfMaps(items, &res); err != nil {
+		return err
+	}
+	str, err := adjustSpecifiedFormat(option.Output, res)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s", str)
+
+	if len(failed) != 0 {
+		return errors.New(strings.Join(failed, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/mocks"
+)
+
+func TestInstance_SQL(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		statement string
+		fileName  string
+		f         func(string) (string, error)
+		option    SQLOption
+		dryRun    bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mocking func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI
+		wantW   string
+		wantErr bool
+	}{
+		{
+			name: "SQL select with parameters over pages",
+			args: args{
+				ctx:       context.Background(),
+				statement: "SELECT * FROM User WHERE ID = ? AND Name = ?",
+				option: SQLOption{
+					Parameters: `[1, {"S": "Alice"}]`,
+					// SELECT does not write, so it is not confirmed.
+					Confirmation: Confirmation{ProtectedTables: []string{"User"}},
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				params := []types.AttributeValue{
+					&types.AttributeValueMemberN{Value: "1"},
+					&types.AttributeValueMemberS{Value: "Alice"},
+				}
+				m.ExecuteStatementClient.On("ExecuteStatement", ctx, &dynamodb.ExecuteStatementInput{
+					Statement:  aws.String("SELECT * FROM User WHERE ID = ? AND Name = ?"),
+					Parameters: params,
+				}).Return(&dynamodb.ExecuteStatementOutput{
+					Items: []map[string]types.AttributeValue{
+						{
+							"ID":   &types.AttributeValueMemberN{Value: "1"},
+							"Name": &types.AttributeValueMemberS{Value: "Alice"},
+						},
+					},
+					NextToken: aws.String("NEXT"),
+				}, nil).Once()
+				m.ExecuteStatementClient.On("ExecuteStatement", ctx, &dynamodb.ExecuteStatementInput{
+					Statement:  aws.String("SELECT * FROM User WHERE ID = ? AND Name = ?"),
+					Parameters: params,
+					NextToken:  aws.String("NEXT"),
+				}).Return(&dynamodb.ExecuteStatementOutput{
+					Items: []map[string]types.AttributeValue{
+						{
+							"ID":   &types.AttributeValueMemberN{Value: "1"},
+							"Name": &types.AttributeValueMemberS{Value: "Alice"},
+							"Age":  &types.AttributeValueMemberN{Value: "20"},
+						},
+					},
+				}, nil).Once()
+
+				return m
+			},
+			wantW: jsonFixture(t, []map[string]interface{}{
+				{
+					"ID":   1,
+					"Name": "Alice",
+				},
+				{
+					"Age":  20,
+					"ID":   1,
+					"Name": "Alice",
+				},
+			}),
+		},
+		{
+			name: "SQL statements from file",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "statements.jsonl",
+				f: func(string) (string, error) {
+					return `{"statement":"SELECT * FROM User WHERE ID = ?","parameters":[1]}
+{"statement":"UPDATE User SET Age = ? WHERE ID = ?","parameters":[20, 2]}`, nil
+				},
+				option: SQLOption{Output: "jsonl"},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.BatchExecuteStatementClient.On("BatchExecuteStatement", ctx, &dynamodb.BatchExecuteStatementInput{
+					Statements: []types.BatchStatementRequest{
+						{
+							Statement: aws.String("SELECT * FROM User WHERE ID = ?"),
+							Parameters: []types.AttributeValue{
+								&types.AttributeValueMemberN{Value: "1"},
+							},
+						},
+						{
+							Statement: aws.String("UPDATE User SET Age = ? WHERE ID = ?"),
+							Parameters: []types.AttributeValue{
+								&types.AttributeValueMemberN{Value: "20"},
+								&types.AttributeValueMemberN{Value: "2"},
+							},
+						},
+					},
+				}).Return(&dynamodb.BatchExecuteStatementOutput{
+					Responses: []types.BatchStatementResponse{
+						{
+							TableName: aws.String("User"),
+							Item: map[string]types.AttributeValue{
+								"ID": &types.AttributeValueMemberN{Value: "1"},
+							},
+						},
+						{
+							TableName: aws.String("User"),
+							Error: &types.BatchStatementError{
+								Code:    types.BatchStatementErrorCodeEnumConditionalCheckFailed,
+								Message: aws.String("The conditional request failed"),
+							},
+						},
+					},
+				}, nil)
+
+				return m
+			},
+			wantW:   "{\"ID\":1}\n",
+			wantErr: true,
+		},
+		{
+			name: "SQL update in dry-run mode",
+			args: args{
+				ctx:       context.Background(),
+				statement: "UPDATE User SET Age = ? WHERE ID = ?",
+				option:    SQLOption{Parameters: `[20, 1]`},
+				dryRun:    true,
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+
+				return m
+			},
+			wantW: jsonFixture(t, &dryRunRequest{
+				Operation: "ExecuteStatement",
+				Statement: "UPDATE User SET Age = ? WHERE ID = ?",
+				Parameters: []map[string]interface{}{
+					{"N": "20"},
+					{"N": "1"},
+				},
+			}) + "[]\n",
+		},
+		{
+			name: "SQL delete from protected table without confirmation",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "statements.jsonl",
+				f: func(string) (string, error) {
+					return `{"statement":"SELECT * FROM User WHERE ID = ?","parameters":[1]}
+{"statement":"DELETE FROM \"prod-User\" WHERE ID = ?","parameters":[2]}`, nil
+				},
+				option: SQLOption{Confirmation: Confirmation{ProtectedTables: []string{"prod-*"}}},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "SQL with both statement and file",
+			args: args{
+				ctx:       context.Background(),
+				statement: "SELECT * FROM User",
+				fileName:  "statements.json",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "SQL with invalid parameters",
+			args: args{
+				ctx:       context.Background(),
+				statement: "SELECT * FROM User WHERE ID = ?",
+				option:    SQLOption{Parameters: `{"ID": 1}`},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: m,
+				DryRun:    tt.args.dryRun,
+			}
+			w := &bytes.Buffer{}
+			err := i.SQL(tt.args.ctx, w, tt.args.statement, tt.args.fileName, fileFixture(t, tt.args.f), tt.args.option)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SQL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("SQL() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			m.ExecuteStatementClient.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_sqlWriteTable(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "SELECT does not write",
+			statement: `SELECT * FROM "User"`,
+		},
+		{
+			name:      "INSERT into quoted table",
+			statement: ` insert into "prod-User" value {'ID': 1}`,
+			want:      "prod-User",
+		},
+		{
+			name:      "UPDATE unquoted table",
+			statement: "UPDATE User SET Age = 20 WHERE ID = 1",
+			want:      "User",
+		},
+		{
+			name:      "DELETE from table whose name has escaped double quote",
+			statement: `DELETE FROM "a""b" WHERE ID = 1`,
+			want:      `a"b`,
+		},
+		{
+			name:      "Error DELETE without FROM",
+			statement: "DELETE User WHERE ID = 1",
+			wantErr:   true,
+		},
+		{
+			name:      "Error unterminated quoted table",
+			statement: `UPDATE "User SET Age = 20`,
+			wantErr:   true,
+		},
+		{
+			name:      "Error unknown statement",
+			statement: "EXISTS(SELECT * FROM User)",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sqlWriteTable(tt.statement)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sqlWriteTable() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("sqlWriteTable() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,8 @@ SCRIPT_ROOT_DIR=$1
 TEST_NAME=$(basename "$0" | sed "s/\..*//")
 
 # aws dynamodb describe-table --table-name User --endpoint-url http://localhost:8000
-CMD="edy desc -t User --local 8000"
+# The creation time and the size depend on when and how the table is created.
+CMD="edy desc -t User --local 8000 | jq 'del(.creationDateTime, .tableSizeBytes)'"
 
 . "${SCRIPT_ROOT_DIR}"/helper.sh
 
@@ -0,0 +1,383 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/client"
+	"github.com/hirano00o/edy/model"
+)
+
+// transactOperation is an operation of the transaction. Only one of the fields is specified.
+type transactOperation struct {
+	Put            *transactTarget `json:"put"`
+	Update         *transactTarget `json:"update"`
+	Delete         *transactTarget `json:"delete"`
+	ConditionCheck *transactTarget `json:"conditionCheck"`
+}
+
+type transactTarget struct {
+	Table string                 `json:"table"`
+	Item  map[string]interface{} `json:"item"`
+	Key   map[string]interface{} `json:"key"`
+	// Set and Remove are the attributes to update. The nested attribute is specified by dot separated path.
+	Set       map[string]interface{} `json:"set"`
+	Remove    []string               `json:"remove"`
+	Condition string                 `json:"condition"`
+}
+
+// transactCancellationReason is the reason why the operation is canceled.
+type transactCancellationReason struct {
+	// Operation is the 1-based index in the input.
+	Operation int    `json:"operation"`
+	Type      string `json:"type"`
+	Table     string `json:"table"`
+	Code      string `json:"code"`
+	Message   string `json:"message,omitempty"`
+}
+
+func (o *transactOperation) target() (string, *transactTarget, error) {
+	var typ string
+	var t *transactTarget
+	for _, v := range []struct {
+		typ string
+		t   *transactTarget
+	}{
+		{"put", o.Put},
+		{"update", o.Update},
+		{"delete", o.Delete},
+		{"conditionCheck", o.ConditionCheck},
+	} {
+		if v.t == nil {
+			continue
+		}
+		if t != nil {
+			return "", nil, fmt.Errorf("only one of put, update, delete and conditionCheck can be specified")
+		}
+		typ, t = v.typ, v.t
+	}
+	if t == nil {
+		return "", nil, fmt.Errorf("required either put, update, delete or conditionCheck")
+	}
+	if len(t.Table) == 0 {
+		return "", nil, fmt.Errorf("required table")
+	}
+	return typ, t, nil
+}
+
+// readJSONObjects reads JSON array of the objects or JSON Lines.
+func readJSONObjects(r io.Reader) ([]json.RawMessage, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.TrimSpace(b)
+	var raws []json.RawMessage
+	if bytes.HasPrefix(b, []byte("[")) {
+		if err := json.Unmarshal(b, &raws); err != nil {
+			return nil, fmt.Errorf("invalid json format: %v", err)
+		}
+		return raws, nil
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	for {
+		var raw json.RawMessage
+		if err := d.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid json format, line %d: %v", len(raws)+1, err)
+		}
+		raws = append(raws, raw)
+	}
+	return raws, nil
+}
+
+// decodeJSONObject decodes the object, whose number is kept as json.Number, and the unknown field is an error.
+func decodeJSONObject(raw json.RawMessage, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	d.DisallowUnknownFields()
+	return d.Decode(v)
+}
+
+// readTransactOperations reads JSON array of the operations or JSON Lines.
+func readTransactOperations(r io.Reader) ([]*transactOperation, error) {
+	raws, err := readJSONObjects(r)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(raws) == 0:
+		return nil, fmt.Errorf("no operation in the input")
+	case len(raws) > model.TransactWriteItemsMax:
+		return nil, fmt.Errorf("too many operations, up to %d: %d", model.TransactWriteItemsMax, len(raws))
+	}
+	ops := make([]*transactOperation, len(raws))
+	for i := range raws {
+		ops[i] = new(transactOperation)
+		if err := decodeJSONObject(raws[i], ops[i]); err != nil {
+			return nil, fmt.Errorf("invalid json format, operation %d: %v", i+1, err)
+		}
+	}
+	return ops, nil
+}
+
+// transactAttributes converts the item or the key, which is plain JSON or DynamoDB JSON.
+func transactAttributes(m map[string]interface{}) (map[string]types.AttributeValue, error) {
+	if isDynamoDBJSONItem(m) {
+		return analyseDynamoDBJSONItem(m)
+	}
+	opt, err := newAttributeOption("", "", "")
+	if err != nil {
+		return nil, err
+	}
+	return recursiveAnalyseJSON(m, "", opt)
+}
+
+func transactUpdate(t *transactTarget) (*expression.UpdateBuilder, error) {
+	if len(t.Set) == 0 && len(t.Remove) == 0 {
+		return nil, fmt.Errorf("required set or remove")
+	}
+	set, err := transactAttributes(t.Set)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(set))
+	for k := range set {
+		names = append(names, k)
+	}
+	// Sort to build the same expression for the same input.
+	sort.Strings(names)
+	var u expression.UpdateBuilder
+	for _, k := range names {
+		u = u.Set(expression.Name(k), expression.Value(set[k]))
+	}
+	for _, k := range t.Remove {
+		u = u.Remove(expression.Name(k))
+	}
+	return &u, nil
+}
+
+func transactWriteItem(typ string, t *transactTarget) (*types.TransactWriteItem, error) {
+	var c *expression.ConditionBuilder
+	if len(t.Condition) != 0 {
+		var err error
+		c, err = analyseFilterCondition(t.Condition)
+		if err != nil {
+			return nil, err
+		}
+	}
+	builder := expression.NewBuilder()
+	if c != nil {
+		builder = builder.WithCondition(*c)
+	}
+
+	var item, key map[string]types.AttributeValue
+	var err error
+	if typ == "put" {
+		if len(t.Item) == 0 {
+			return nil, fmt.Errorf("required item")
+		}
+		item, err = transactAttributes(t.Item)
+	} else {
+		if len(t.Key) == 0 {
+			return nil, fmt.Errorf("required key")
+		}
+		key, err = transactAttributes(t.Key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if typ == "update" {
+		u, err := transactUpdate(t)
+		if err != nil {
+			return nil, err
+		}
+		builder = builder.WithUpdate(*u)
+	} else if typ == "conditionCheck" && c == nil {
+		return nil, fmt.Errorf("required condition")
+	}
+
+	var expr expression.Expression
+	if c != nil || typ == "update" {
+		expr, err = builder.Build()
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch typ {
+	case "put":
+		return &types.TransactWriteItem{Put: &types.Put{
+			TableName:                 aws.String(t.Table),
+			Item:                      item,
+			ConditionExpression:       expr.Condition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expressionValues(expr),
+		}}, nil
+	case "update":
+		return &types.TransactWriteItem{Update: &types.Update{
+			TableName:                 aws.String(t.Table),
+			Key:                       key,
+			UpdateExpression:          expr.Update(),
+			ConditionExpression:       expr.Condition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expressionValues(expr),
+		}}, nil
+	case "delete":
+		return &types.TransactWriteItem{Delete: &types.Delete{
+			TableName:                 aws.String(t.Table),
+			Key:                       key,
+			ConditionExpression:       expr.Condition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expressionValues(expr),
+		}}, nil
+	default:
+		return &types.TransactWriteItem{ConditionCheck: &types.ConditionCheck{
+			TableName:                 aws.String(t.Table),
+			Key:                       key,
+			ConditionExpression:       expr.Condition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expressionValues(expr),
+		}}, nil
+	}
+}
+
+// transactInput builds the input of TransactWriteItems. The types and the tables of the operations are
+// returned to report the cancellation reasons.
+func transactInput(
+	ops []*transactOperation,
+	token string,
+) (input *dynamodb.TransactWriteItemsInput, opTypes, tables []string, err error) {
+	input = &dynamodb.TransactWriteItemsInput{
+		TransactItems: make([]types.TransactWriteItem, len(ops)),
+	}
+	if len(token) != 0 {
+		input.ClientRequestToken = aws.String(token)
+	}
+	opTypes = make([]string, len(ops))
+	tables = make([]string, len(ops))
+	for i := range ops {
+		typ, t, err := ops[i].target()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("operation %d: %v", i+1, err)
+		}
+		item, err := transactWriteItem(typ, t)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("operation %d: %v", i+1, err)
+		}
+		input.TransactItems[i] = *item
+		opTypes[i], tables[i] = typ, t.Table
+	}
+	return input, opTypes, tables, nil
+}
+
+func transactWriteItems(
+	ctx context.Context,
+	input *dynamodb.TransactWriteItemsInput,
+	opTypes,
+	tables []string,
+) (map[string]interface{}, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	_, err := cli.TransactWriteItems(ctx, input)
+	var tce *types.TransactionCanceledException
+	if errors.As(err, &tce) {
+		reasons := make([]*transactCancellationReason, 0, len(tce.CancellationReasons))
+		for i, r := range tce.CancellationReasons {
+			if i >= len(opTypes) {
+				break
+			}
+			reasons = append(reasons, &transactCancellationReason{
+				Operation: i + 1,
+				Type:      opTypes[i],
+				Table:     tables[i],
+				Code:      aws.ToString(r.Code),
+				Message:   aws.ToString(r.Message),
+			})
+		}
+		return map[string]interface{}{"cancellationReasons": reasons}, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"transacted": len(input.TransactItems)}, nil
+}
+
+// confirmTransact asks the user once for each protected table which the transaction writes into.
+// The table which is only checked by conditionCheck is not written.
+func confirmTransact(c *Confirmation, opTypes, tables []string) error {
+	confirmed := make(map[string]struct{}, len(tables))
+	for i := range tables {
+		if opTypes[i] == "conditionCheck" {
+			continue
+		}
+		if _, ok := confirmed[tables[i]]; ok {
+			continue
+		}
+		if err := c.confirmWrite(tables[i], "written"); err != nil {
+			return err
+		}
+		confirmed[tables[i]] = struct{}{}
+	}
+	return nil
+}
+
+func (i *Instance) Transact(
+	ctx context.Context,
+	w io.Writer,
+	fileName string,
+	f func(string) (io.ReadCloser, error),
+	option TransactOption,
+) error {
+	if len(fileName) == 0 {
+		return fmt.Errorf("required --input-file option")
+	}
+	in, err := f(fileName)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	ops, err := readTransactOperations(in)
+	if err != nil {
+		return err
+	}
+	input, opTypes, tables, err := transactInput(ops, option.ClientRequestToken)
+	if err != nil {
+		return err
+	}
+	if !i.DryRun {
+		if err := confirmTransact(&option.Confirmation, opTypes, tables); err != nil {
+			return err
+		}
+	}
+
+	cli := i.createInstance(w)
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	res, err := transactWriteItems(ctx, input, opTypes, tables)
+	if res == nil {
+		return err
+	}
+
+	b, mErr := json.MarshalIndent(res, "", strings.Repeat(" ", 2))
+	if mErr != nil {
+		return mErr
+	}
+	fmt.Fprintf(w, "%s\n", string(b))
+
+	if err != nil {
+		return fmt.Errorf("transaction is canceled")
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/mocks"
+)
+
+func TestInstance_Transact(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		fileName string
+		f        func(string) (string, error)
+		option   TransactOption
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mocking func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI
+		wantW   string
+		wantErr bool
+	}{
+		{
+			name: "Transact put and delete across tables",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "ops.jsonl",
+				f: func(string) (string, error) {
+					return `{"put":{"table":"USER","item":{"ID":3,"Name":"Alice"}}}
+{"delete":{"table":"SESSION","key":{"ID":{"S":"S1"}}}}`, nil
+				},
+				option: TransactOption{ClientRequestToken: "TOKEN"},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.TransactWriteItemsClient.On("TransactWriteItems", ctx, &dynamodb.TransactWriteItemsInput{
+					ClientRequestToken: aws.String("TOKEN"),
+					TransactItems: []types.TransactWriteItem{
+						{
+							Put: &types.Put{
+								TableName: aws.String("USER"),
+								Item: map[string]types.AttributeValue{
+									"ID":   &types.AttributeValueMemberN{Value: "3"},
+									"Name": &types.AttributeValueMemberS{Value: "Alice"},
+								},
+							},
+						},
+						{
+							Delete: &types.Delete{
+								TableName: aws.String("SESSION"),
+								Key: map[string]types.AttributeValue{
+									"ID": &types.AttributeValueMemberS{Value: "S1"},
+								},
+							},
+						},
+					},
+				}).Return(&dynamodb.TransactWriteItemsOutput{}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"transacted\": 2\n}\n",
+		},
+		{
+			name: "Transact update and condition check",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "ops.json",
+				f: func(string) (string, error) {
+					return `[
+  {"update":{"table":"COUNT","key":{"Name":"users"},"set":{"Count":3},"remove":["Old"],"condition":"Count,N = 2"}},
+  {"conditionCheck":{"table":"USER","key":{"ID":3},"condition":"ID,N exists"}}
+]`, nil
+				},
+				// USER is only checked, so it is not confirmed.
+				option: TransactOption{Confirmation: Confirmation{ProtectedTables: []string{"USER"}}},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.TransactWriteItemsClient.On("TransactWriteItems", ctx, &dynamodb.TransactWriteItemsInput{
+					TransactItems: []types.TransactWriteItem{
+						{
+							Update: &types.Update{
+								TableName: aws.String("COUNT"),
+								Key: map[string]types.AttributeValue{
+									"Name": &types.AttributeValueMemberS{Value: "users"},
+								},
+								UpdateExpression:    aws.String("REMOVE #1\nSET #0 = :1\n"),
+								ConditionExpression: aws.String("#0 = :0"),
+								ExpressionAttributeNames: map[string]string{
+									"#0": "Count",
+									"#1": "Old",
+								},
+								ExpressionAttributeValues: map[string]types.AttributeValue{
+									":0": &types.AttributeValueMemberN{Value: "2"},
+									":1": &types.AttributeValueMemberN{Value: "3"},
+								},
+							},
+						},
+						{
+							ConditionCheck: &types.ConditionCheck{
+								TableName: aws.String("USER"),
+								Key: map[string]types.AttributeValue{
+									"ID": &types.AttributeValueMemberN{Value: "3"},
+								},
+								ConditionExpression:      aws.String("attribute_exists (#0)"),
+								ExpressionAttributeNames: map[string]string{"#0": "ID"},
+							},
+						},
+					},
+				}).Return(&dynamodb.TransactWriteItemsOutput{}, nil)
+
+				return m
+			},
+			wantW: "{\n  \"transacted\": 2\n}\n",
+		},
+		{
+			name: "Transact is canceled",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "ops.jsonl",
+				f: func(string) (string, error) {
+					return `{"put":{"table":"USER","item":{"ID":3},"condition":"not ID,N exists"}}
+{"delete":{"table":"SESSION","key":{"ID":"S1"}}}`, nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.TransactWriteItemsClient.On("TransactWriteItems", ctx, &dynamodb.TransactWriteItemsInput{
+					TransactItems: []types.TransactWriteItem{
+						{
+							Put: &types.Put{
+								TableName: aws.String("USER"),
+								Item: map[string]types.AttributeValue{
+									"ID": &types.AttributeValueMemberN{Value: "3"},
+								},
+								ConditionExpression:      aws.String("attribute_not_exists (#0)"),
+								ExpressionAttributeNames: map[string]string{"#0": "ID"},
+							},
+						},
+						{
+							Delete: &types.Delete{
+								TableName: aws.String("SESSION"),
+								Key: map[string]types.AttributeValue{
+									"ID": &types.AttributeValueMemberS{Value: "S1"},
+								},
+							},
+						},
+					},
+				}).Return(nil, &types.TransactionCanceledException{
+					Message: aws.String("Transaction cancelled"),
+					CancellationReasons: []types.CancellationReason{
+						{
+							Code:    aws.String("ConditionalCheckFailed"),
+							Message: aws.String("The conditional request failed"),
+						},
+						{
+							Code: aws.String("None"),
+						},
+					},
+				})
+
+				return m
+			},
+			wantW: jsonFixture(t, map[string]interface{}{
+				"cancellationReasons": []*transactCancellationReason{
+					{
+						Operation: 1,
+						Type:      "put",
+						Table:     "USER",
+						Code:      "ConditionalCheckFailed",
+						Message:   "The conditional request failed",
+					},
+					{
+						Operation: 2,
+						Type:      "delete",
+						Table:     "SESSION",
+						Code:      "None",
+					},
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "Transact into protected table without confirmation",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "ops.jsonl",
+				f: func(string) (string, error) {
+					return `{"put":{"table":"USER","item":{"ID":3,"Name":"Alice"}}}
+{"delete":{"table":"prod-SESSION","key":{"ID":{"S":"S1"}}}}`, nil
+				},
+				option: TransactOption{Confirmation: Confirmation{ProtectedTables: []string{"prod-*"}}},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Transact without input file",
+			args: args{
+				ctx: context.Background(),
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Transact an operation of several types",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "ops.jsonl",
+				f: func(string) (string, error) {
+					return `{"put":{"table":"USER","item":{"ID":3}},"delete":{"table":"USER","key":{"ID":3}}}`, nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Transact condition check without condition",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "ops.jsonl",
+				f: func(string) (string, error) {
+					return `{"conditionCheck":{"table":"USER","key":{"ID":3}}}`, nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: m,
+			}
+			w := &bytes.Buffer{}
+			err := i.Transact(tt.args.ctx, w, tt.args.fileName, fileFixture(t, tt.args.f), tt.args.option)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Transact() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("Transact() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func Test_readTransactOperations(t *testing.T) {
+	ops := bytes.Repeat([]byte(`{"delete":{"table":"USER","key":{"ID":3}}}`+"\n"), 101)
+	if _, err := readTransactOperations(bytes.NewReader(ops)); err == nil {
+		t.Errorf("readTransactOperations() error = nil, want too many operations")
+	}
+	if _, err := readTransactOperations(bytes.NewReader([]byte(`{"unknown":{}}`))); err == nil {
+		t.Errorf("readTransactOperations() error = nil, want invalid json format")
+	}
+}
@@ -0,0 +1,167 @@
+package edy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/client"
+	"github.com/hirano00o/edy/model"
+)
+
+// transactGet is the item to get in the transaction.
+type transactGet struct {
+	Table      string                 `json:"table"`
+	Key        map[string]interface{} `json:"key"`
+	Projection string                 `json:"projection"`
+}
+
+// readTransactGets reads JSON array of the items to get or JSON Lines.
+func readTransactGets(r io.Reader) ([]*transactGet, error) {
+	raws, err := readJSONObjects(r)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(raws) == 0:
+		return nil, fmt.Errorf("no key in the input")
+	case len(raws) > model.TransactGetItemsMax:
+		return nil, fmt.Errorf("too many keys, up to %d: %d", model.TransactGetItemsMax, len(raws))
+	}
+	gets := make([]*transactGet, len(raws))
+	for i := range raws {
+		gets[i] = new(transactGet)
+		if err := decodeJSONObject(raws[i], gets[i]); err != nil {
+			return nil, fmt.Errorf("invalid json format, key %d: %v", i+1, err)
+		}
+	}
+	return gets, nil
+}
+
+func transactGetInput(gets []*transactGet) (*dynamodb.TransactGetItemsInput, error) {
+	input := &dynamodb.TransactGetItemsInput{
+		TransactItems: make([]types.TransactGetItem, len(gets)),
+	}
+	for i, g := range gets {
+		switch {
+		case len(g.Table) == 0:
+			return nil, fmt.Errorf("key %d: required table", i+1)
+		case len(g.Key) == 0:
+			return nil, fmt.Errorf("key %d: required key", i+1)
+		}
+		key, err := transactAttributes(g.Key)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %v", i+1, err)
+		}
+		get := &types.Get{
+			TableName: aws.String(g.Table),
+			Key:       key,
+		}
+		if len(g.Projection) != 0 {
+			expr, err := expression.NewBuilder().WithProjection(*analyseProjection(g.Projection)).Build()
+			if err != nil {
+				return nil, fmt.Errorf("key %d: %v", i+1, err)
+			}
+			get.ProjectionExpression = expr.Projection()
+			get.ExpressionAttributeNames = expr.Names()
+		}
+		input.TransactItems[i] = types.TransactGetItem{Get: get}
+	}
+	return input, nil
+}
+
+// transactGetItems returns the items in the order of the input. The items which do not exist are skipped,
+// and the indexes of their keys are returned as missing.
+func transactGetItems(
+	ctx context.Context,
+	input *dynamodb.TransactGetItemsInput,
+) ([]map[string]interface{}, []int, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	res, err := cli.TransactGetItems(ctx, input)
+	var tce *types.TransactionCanceledException
+	if errors.As(err, &tce) {
+		var reasons []string
+		for i, r := range tce.CancellationReasons {
+			if code := aws.ToString(r.Code); len(code) != 0 && code != "None" {
+				reasons = append(reasons, fmt.Sprintf("key %d: %s", i+1, code))
+			}
+		}
+		return nil, nil, fmt.Errorf("transaction is canceled: %s", strings.Join(reasons, ", "))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]map[string]types.AttributeValue, 0, len(res.Responses))
+	var missing []int
+	for i := range input.TransactItems {
+		if i < len(res.Responses) && len(res.Responses[i].Item) != 0 {
+			items = append(items, res.Responses[i].Item)
+		} else {
+			missing = append(missing, i)
+		}
+	}
+	resMap := make([]map[string]interface{}, 0, len(items))
+	if err := attributevalue.UnmarshalListOfMaps(items, &resMap); err != nil {
+		return nil, nil, err
+	}
+	return resMap, missing, nil
+}
+
+func (i *Instance) TransactGet(
+	ctx context.Context,
+	w io.Writer,
+	fileName string,
+	f func(string) (io.ReadCloser, error),
+	option TransactGetOption,
+) error {
+	if len(fileName) == 0 {
+		return fmt.Errorf("required --input-file option")
+	}
+	in, err := f(fileName)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	gets, err := readTransactGets(in)
+	if err != nil {
+		return err
+	}
+	input, err := transactGetInput(gets)
+	if err != nil {
+		return err
+	}
+
+	cli := i.NewClient.CreateInstance()
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	res, missing, err := transactGetItems(ctx, input)
+	if err != nil {
+		return err
+	}
+	for _, n := range missing {
+		key, err := json.Marshal(gets[n].Key)
+		if err != nil {
+			return err
+		}
+		progressf(option.Warning, "key %d of %s matches no item: %s\n", n+1, gets[n].Table, key)
+	}
+
+	str, err := adjustSpecifiedFormat(option.Output, res)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s", str)
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/mocks"
+)
+
+func TestInstance_TransactGet(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		fileName string
+		f        func(string) (string, error)
+		output   string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		mocking     func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI
+		wantW       string
+		wantWarning string
+		wantErr     bool
+	}{
+		{
+			name: "TransactGet across tables with the key which matches no item",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "keys.jsonl",
+				f: func(string) (string, error) {
+					return `{"table":"ORDER","key":{"ID":1}}
+{"table":"LEDGER","key":{"OrderID":1,"Seq":2},"projection":"Amount"}
+{"table":"LEDGER","key":{"OrderID":1,"Seq":3}}`, nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.TransactGetItemsClient.On("TransactGetItems", ctx, &dynamodb.TransactGetItemsInput{
+					TransactItems: []types.TransactGetItem{
+						{
+							Get: &types.Get{
+								TableName: aws.String("ORDER"),
+								Key: map[string]types.AttributeValue{
+									"ID": &types.AttributeValueMemberN{Value: "1"},
+								},
+							},
+						},
+						{
+							Get: &types.Get{
+								TableName: aws.String("LEDGER"),
+								Key: map[string]types.AttributeValue{
+									"OrderID": &types.AttributeValueMemberN{Value: "1"},
+									"Seq":     &types.AttributeValueMemberN{Value: "2"},
+								},
+								ProjectionExpression:     aws.String("#0"),
+								ExpressionAttributeNames: map[string]string{"#0": "Amount"},
+							},
+						},
+						{
+							Get: &types.Get{
+								TableName: aws.String("LEDGER"),
+								Key: map[string]types.AttributeValue{
+									"OrderID": &types.AttributeValueMemberN{Value: "1"},
+									"Seq":     &types.AttributeValueMemberN{Value: "3"},
+								},
+							},
+						},
+					},
+				}).Return(&dynamodb.TransactGetItemsOutput{
+					Responses: []types.ItemResponse{
+						{
+							Item: map[string]types.AttributeValue{
+								"ID":    &types.AttributeValueMemberN{Value: "1"},
+								"Total": &types.AttributeValueMemberN{Value: "100"},
+							},
+						},
+						{
+							Item: map[string]types.AttributeValue{
+								"Amount": &types.AttributeValueMemberN{Value: "100"},
+							},
+						},
+						{},
+					},
+				}, nil)
+
+				return m
+			},
+			wantW: jsonFixture(t, []map[string]interface{}{
+				{
+					"ID":    1,
+					"Total": 100,
+				},
+				{
+					"Amount": 100,
+				},
+			}),
+			wantWarning: "key 3 of LEDGER matches no item: {\"OrderID\":1,\"Seq\":3}\n",
+		},
+		{
+			name: "TransactGet with csv output",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "keys.json",
+				f: func(string) (string, error) {
+					return `[{"table":"ORDER","key":{"ID":1}}]`, nil
+				},
+				output: "csv",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.TransactGetItemsClient.On("TransactGetItems", ctx, &dynamodb.TransactGetItemsInput{
+					TransactItems: []types.TransactGetItem{
+						{
+							Get: &types.Get{
+								TableName: aws.String("ORDER"),
+								Key: map[string]types.AttributeValue{
+									"ID": &types.AttributeValueMemberN{Value: "1"},
+								},
+							},
+						},
+					},
+				}).Return(&dynamodb.TransactGetItemsOutput{
+					Responses: []types.ItemResponse{
+						{
+							Item: map[string]types.AttributeValue{
+								"ID":    &types.AttributeValueMemberN{Value: "1"},
+								"Total": &types.AttributeValueMemberN{Value: "100"},
+							},
+						},
+					},
+				}, nil)
+
+				return m
+			},
+			wantW: "ID,Total\n1,100\n",
+		},
+		{
+			name: "TransactGet is canceled",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "keys.json",
+				f: func(string) (string, error) {
+					return `[{"table":"ORDER","key":{"ID":1}}]`, nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.TransactGetItemsClient.On("TransactGetItems", ctx, &dynamodb.TransactGetItemsInput{
+					TransactItems: []types.TransactGetItem{
+						{
+							Get: &types.Get{
+								TableName: aws.String("ORDER"),
+								Key: map[string]types.AttributeValue{
+									"ID": &types.AttributeValueMemberN{Value: "1"},
+								},
+							},
+						},
+					},
+				}).Return(nil, &types.TransactionCanceledException{
+					CancellationReasons: []types.CancellationReason{
+						{Code: aws.String("TransactionConflict")},
+					},
+				})
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "TransactGet without table",
+			args: args{
+				ctx:      context.Background(),
+				fileName: "keys.json",
+				f: func(string) (string, error) {
+					return `[{"key":{"ID":1}}]`, nil
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				return new(mocks.MockDynamoDBAPI)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: m,
+			}
+			w := &bytes.Buffer{}
+			warning := &bytes.Buffer{}
+			err := i.TransactGet(tt.args.ctx, w, tt.args.fileName, fileFixture(t, tt.args.f), TransactGetOption{
+				Output:  tt.args.output,
+				Warning: warning,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TransactGet() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("TransactGet() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			if gotWarning := warning.String(); gotWarning != tt.wantWarning {
+				t.Errorf("TransactGet() gotWarning = %v, want %v", gotWarning, tt.wantWarning)
+			}
+		})
+	}
+}
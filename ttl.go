@@ -0,0 +1,181 @@
+package edy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/client"
+	"github.com/hirano00o/edy/model"
+)
+
+// now is replaced in tests.
+var now = time.Now
+
+func describeTTL(ctx context.Context, tableName string) (*model.TTL, error) {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	res, err := cli.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	d := res.TimeToLiveDescription
+	if d == nil {
+		return nil, nil
+	}
+	ttl := &model.TTL{Status: string(d.TimeToLiveStatus)}
+	switch d.TimeToLiveStatus {
+	case types.TimeToLiveStatusEnabled, types.TimeToLiveStatusEnabling:
+		ttl.AttributeName = aws.ToString(d.AttributeName)
+	}
+	return ttl, nil
+}
+
+func updateTTL(ctx context.Context, tableName, attributeName string, enabled bool) error {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+	_, err := cli.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(tableName),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(attributeName),
+			Enabled:       aws.Bool(enabled),
+		},
+	})
+	return err
+}
+
+// parseTTLDuration parses the duration such as 7d, 2w and 36h. d is a day and w is a week,
+// and the others are the same as time.ParseDuration.
+func parseTTLDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	var d time.Duration
+	if unit != 0 {
+		n, err := strconv.ParseFloat(s[:len(s)-1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --ttl-in duration, ex. 7d, 2w, 36h: %s", s)
+		}
+		d = time.Duration(n * float64(unit))
+	} else {
+		var err error
+		d, err = time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --ttl-in duration, ex. 7d, 2w, 36h: %s", s)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("--ttl-in duration must be positive: %s", s)
+	}
+	return d, nil
+}
+
+// ttlItemReader sets the TTL attribute of the items to the expiration time in epoch seconds.
+type ttlItemReader struct {
+	itemReader
+	attributeName string
+	expiresAt     types.AttributeValue
+}
+
+func (r *ttlItemReader) set(item map[string]types.AttributeValue) {
+	item[r.attributeName] = r.expiresAt
+}
+
+func (r *ttlItemReader) next() (map[string]types.AttributeValue, error) {
+	item, err := r.itemReader.next()
+	if err != nil {
+		return nil, err
+	}
+	r.set(item)
+	return item, nil
+}
+
+// withTTL sets the TTL attribute of head which has already been read from r, and wraps r to set the rest of items.
+// The attribute is the TTL attribute of the table, so TTL must be enabled.
+func withTTL(
+	ctx context.Context,
+	tableName string,
+	d time.Duration,
+	head []map[string]types.AttributeValue,
+	r itemReader,
+) (itemReader, error) {
+	ttl, err := describeTTL(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if ttl == nil || len(ttl.AttributeName) == 0 {
+		return nil, fmt.Errorf("TTL is not enabled on %s, enable it by edy ttl enable", tableName)
+	}
+	tr := &ttlItemReader{
+		itemReader:    r,
+		attributeName: ttl.AttributeName,
+		expiresAt: &types.AttributeValueMemberN{
+			Value: strconv.FormatInt(now().Add(d).Unix(), 10),
+		},
+	}
+	for i := range head {
+		tr.set(head[i])
+	}
+	return tr, nil
+}
+
+// DescribeTTL shows the TTL attribute and the status of the table.
+func (i *Instance) DescribeTTL(ctx context.Context, w io.Writer, tableName string) error {
+	cli := i.NewClient.CreateInstance()
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	ttl, err := describeTTL(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	if ttl == nil {
+		ttl = &model.TTL{}
+	}
+	return printJSON(w, ttl)
+}
+
+// UpdateTTL enables or disables TTL of the table. attributeName can be empty to disable,
+// then the current TTL attribute is disabled.
+func (i *Instance) UpdateTTL(ctx context.Context, w io.Writer, tableName, attributeName string, enabled bool) error {
+	cli := i.createInstance(w)
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	if len(attributeName) == 0 {
+		if enabled {
+			return fmt.Errorf("required --attribute-name option")
+		}
+		ttl, err := describeTTL(ctx, tableName)
+		if err != nil {
+			return err
+		}
+		if ttl == nil || len(ttl.AttributeName) == 0 {
+			return fmt.Errorf("TTL is not enabled on %s", tableName)
+		}
+		attributeName = ttl.AttributeName
+	}
+	if err := updateTTL(ctx, tableName, attributeName, enabled); err != nil {
+		return err
+	}
+	if i.DryRun {
+		return nil
+	}
+
+	ttl, err := describeTTL(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	return printJSON(w, ttl)
+}
@@ -0,0 +1,195 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/mocks"
+	"github.com/hirano00o/edy/model"
+)
+
+func Test_parseTTLDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "Days", s: "7d", want: 7 * 24 * time.Hour},
+		{name: "Weeks", s: "2w", want: 14 * 24 * time.Hour},
+		{name: "Fractional days", s: "1.5d", want: 36 * time.Hour},
+		{name: "Go duration", s: "1h30m", want: 90 * time.Minute},
+		{name: "Without unit", s: "7", wantErr: true},
+		{name: "Negative", s: "-1d", wantErr: true},
+		{name: "Invalid", s: "xd", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTTLDuration(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseTTLDuration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseTTLDuration() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstance_UpdateTTL(t *testing.T) {
+	type args struct {
+		ctx           context.Context
+		tableName     string
+		attributeName string
+		enabled       bool
+		dryRun        bool
+	}
+	describeTTLOutput := func(status types.TimeToLiveStatus) *dynamodb.DescribeTimeToLiveOutput {
+		return &dynamodb.DescribeTimeToLiveOutput{
+			TimeToLiveDescription: &types.TimeToLiveDescription{
+				AttributeName:    aws.String("ExpiresAt"),
+				TimeToLiveStatus: status,
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mocking func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI
+		wantW   string
+		wantErr bool
+	}{
+		{
+			name: "Enable TTL",
+			args: args{
+				ctx:           context.Background(),
+				tableName:     "TEST",
+				attributeName: "ExpiresAt",
+				enabled:       true,
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.UpdateTimeToLiveClient.On("UpdateTimeToLive", ctx, &dynamodb.UpdateTimeToLiveInput{
+					TableName: aws.String("TEST"),
+					TimeToLiveSpecification: &types.TimeToLiveSpecification{
+						AttributeName: aws.String("ExpiresAt"),
+						Enabled:       aws.Bool(true),
+					},
+				}).Return(&dynamodb.UpdateTimeToLiveOutput{}, nil)
+				m.DescribeTimeToLiveClient.On("DescribeTimeToLive", ctx, &dynamodb.DescribeTimeToLiveInput{
+					TableName: aws.String("TEST"),
+				}).Return(describeTTLOutput(types.TimeToLiveStatusEnabling), nil)
+
+				return m
+			},
+			wantW: jsonFixture(t, &model.TTL{AttributeName: "ExpiresAt", Status: "ENABLING"}),
+		},
+		{
+			name: "Disable the current TTL attribute",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				input := &dynamodb.DescribeTimeToLiveInput{TableName: aws.String("TEST")}
+				m.DescribeTimeToLiveClient.On("DescribeTimeToLive", ctx, input).
+					Return(describeTTLOutput(types.TimeToLiveStatusEnabled), nil).Once()
+				m.UpdateTimeToLiveClient.On("UpdateTimeToLive", ctx, &dynamodb.UpdateTimeToLiveInput{
+					TableName: aws.String("TEST"),
+					TimeToLiveSpecification: &types.TimeToLiveSpecification{
+						AttributeName: aws.String("ExpiresAt"),
+						Enabled:       aws.Bool(false),
+					},
+				}).Return(&dynamodb.UpdateTimeToLiveOutput{}, nil)
+				m.DescribeTimeToLiveClient.On("DescribeTimeToLive", ctx, input).
+					Return(describeTTLOutput(types.TimeToLiveStatusDisabling), nil)
+
+				return m
+			},
+			wantW: jsonFixture(t, &model.TTL{Status: "DISABLING"}),
+		},
+		{
+			name: "Enable TTL in dry-run mode",
+			args: args{
+				ctx:           context.Background(),
+				tableName:     "TEST",
+				attributeName: "ExpiresAt",
+				enabled:       true,
+				dryRun:        true,
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+
+				return m
+			},
+			wantW: jsonFixture(t, &dryRunRequest{
+				Operation: "UpdateTimeToLive",
+				TableName: "TEST",
+				Input: &types.TimeToLiveSpecification{
+					AttributeName: aws.String("ExpiresAt"),
+					Enabled:       aws.Bool(true),
+				},
+			}),
+		},
+		{
+			name: "Disable TTL which is not enabled",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTimeToLiveClient.On("DescribeTimeToLive", ctx, &dynamodb.DescribeTimeToLiveInput{
+					TableName: aws.String("TEST"),
+				}).Return(&dynamodb.DescribeTimeToLiveOutput{
+					TimeToLiveDescription: &types.TimeToLiveDescription{TimeToLiveStatus: types.TimeToLiveStatusDisabled},
+				}, nil)
+
+				return m
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: m,
+				DryRun:    tt.args.dryRun,
+			}
+			w := &bytes.Buffer{}
+			err := i.UpdateTTL(tt.args.ctx, w, tt.args.tableName, tt.args.attributeName, tt.args.enabled)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateTTL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("UpdateTTL() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			m.UpdateTimeToLiveClient.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package edy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/model"
+)
+
+func findGSI(t *model.Table, name string) *model.GlobalSecondaryIndex {
+	for i := range t.GSI {
+		if t.GSI[i].Name == name {
+			return t.GSI[i]
+		}
+	}
+	return nil
+}
+
+// parseGSICapacity parses the capacity of the index written as INDEX:READ:WRITE.
+func parseGSICapacity(s string) (string, *types.ProvisionedThroughput, error) {
+	f := strings.Split(s, ":")
+	if len(f) != 3 {
+		return "", nil, fmt.Errorf("invalid index capacity, the format is INDEX:READ:WRITE: %s", s)
+	}
+	read, err := strconv.ParseInt(strings.TrimSpace(f[1]), 10, 64)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid read capacity of %s: %v", f[0], err)
+	}
+	write, err := strconv.ParseInt(strings.TrimSpace(f[2]), 10, 64)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid write capacity of %s: %v", f[0], err)
+	}
+	return strings.TrimSpace(f[0]), &types.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(read),
+		WriteCapacityUnits: aws.Int64(write),
+	}, nil
+}
+
+func updateTableInput(t *model.Table, option UpdateTableOption) (*dynamodb.UpdateTableInput, error) {
+	input := &dynamodb.UpdateTableInput{
+		TableName: aws.String(t.Name),
+	}
+	updated := false
+
+	current := types.BillingMode(t.BillingMode)
+	if len(current) == 0 {
+		current = types.BillingModePayPerRequest
+	}
+	mode := current
+	if len(option.BillingMode) != 0 {
+		mode = types.BillingMode(strings.ToUpper(option.BillingMode))
+		switch mode {
+		case types.BillingModePayPerRequest, types.BillingModeProvisioned:
+		default:
+			return nil, fmt.Errorf(
+				"invalid billing mode, available mode is PAY_PER_REQUEST, PROVISIONED: %s", option.BillingMode)
+		}
+		input.BillingMode = mode
+		updated = true
+	}
+	switching := mode == types.BillingModeProvisioned && current != types.BillingModeProvisioned
+
+	// The capacity of the table is required when switching to PROVISIONED.
+	var throughput *types.ProvisionedThroughput
+	if option.ReadCapacity != 0 || option.WriteCapacity != 0 || switching {
+		if mode != types.BillingModeProvisioned {
+			return nil, fmt.Errorf("capacity can be changed only in PROVISIONED billing mode")
+		}
+		read, write := option.ReadCapacity, option.WriteCapacity
+		if t.ProvisionedThroughput != nil {
+			if read == 0 {
+				read = t.ProvisionedThroughput.ReadCapacityUnits
+			}
+			if write == 0 {
+				write = t.ProvisionedThroughput.WriteCapacityUnits
+			}
+		}
+		if read == 0 || write == 0 {
+			return nil, fmt.Errorf("required --read-capacity and --write-capacity for PROVISIONED billing mode")
+		}
+		throughput = &types.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(read),
+			WriteCapacityUnits: aws.Int64(write),
+		}
+		input.ProvisionedThroughput = throughput
+		updated = true
+	} else if mode == types.BillingModeProvisioned {
+		throughput = provisionedThroughput(t.ProvisionedThroughput)
+	}
+
+	capacities := make(map[string]*types.ProvisionedThroughput)
+	for _, c := range option.GSICapacity {
+		name, p, err := parseGSICapacity(c)
+		if err != nil {
+			return nil, err
+		}
+		if findGSI(t, name) == nil {
+			return nil, fmt.Errorf("gsi %s does not exist in %s", name, t.Name)
+		}
+		if mode != types.BillingModeProvisioned {
+			return nil, fmt.Errorf("capacity can be changed only in PROVISIONED billing mode")
+		}
+		capacities[name] = p
+	}
+	for _, g := range t.GSI {
+		p, ok := capacities[g.Name]
+		if !ok && switching {
+			// The indexes also need the capacity when switching to PROVISIONED.
+			p = throughput
+		}
+		if p == nil || g.Name == option.DeleteGSI {
+			continue
+		}
+		input.GlobalSecondaryIndexUpdates = append(input.GlobalSecondaryIndexUpdates, types.GlobalSecondaryIndexUpdate{
+			Update: &types.UpdateGlobalSecondaryIndexAction{
+				IndexName:             aws.String(g.Name),
+				ProvisionedThroughput: p,
+			},
+		})
+		updated = true
+	}
+
+	if len(option.AddGSI) != 0 {
+		var g model.GlobalSecondaryIndex
+		if err := decodeSpec([]byte(option.AddGSI), &g); err != nil {
+			return nil, err
+		}
+		if findGSI(t, g.Name) != nil {
+			return nil, fmt.Errorf("gsi %s already exists in %s", g.Name, t.Name)
+		}
+		attrs := &attributeDefinitions{types: make(map[string]string)}
+		gsi, err := attrs.globalSecondaryIndex(&g, throughput)
+		if err != nil {
+			return nil, err
+		}
+		input.AttributeDefinitions = attrs.defs
+		input.GlobalSecondaryIndexUpdates = append(input.GlobalSecondaryIndexUpdates, types.GlobalSecondaryIndexUpdate{
+			Create: &types.CreateGlobalSecondaryIndexAction{
+				IndexName:             gsi.IndexName,
+				KeySchema:             gsi.KeySchema,
+				Projection:            gsi.Projection,
+				ProvisionedThroughput: gsi.ProvisionedThroughput,
+			},
+		})
+		updated = true
+	}
+
+	if len(option.DeleteGSI) != 0 {
+		if findGSI(t, option.DeleteGSI) == nil {
+			return nil, fmt.Errorf("gsi %s does not exist in %s", option.DeleteGSI, t.Name)
+		}
+		input.GlobalSecondaryIndexUpdates = append(input.GlobalSecondaryIndexUpdates, types.GlobalSecondaryIndexUpdate{
+			Delete: &types.DeleteGlobalSecondaryIndexAction{IndexName: aws.String(option.DeleteGSI)},
+		})
+		updated = true
+	}
+
+	if len(option.Stream) != 0 {
+		if strings.EqualFold(option.Stream, "DISABLED") {
+			input.StreamSpecification = &types.StreamSpecification{StreamEnabled: aws.Bool(false)}
+		} else {
+			input.StreamSpecification = &types.StreamSpecification{
+				StreamEnabled:  aws.Bool(true),
+				StreamViewType: types.StreamViewType(strings.ToUpper(option.Stream)),
+			}
+		}
+		updated = true
+	}
+
+	if len(option.TableClass) != 0 {
+		c, err := tableClass(option.TableClass)
+		if err != nil {
+			return nil, err
+		}
+		input.TableClass = c
+		updated = true
+	}
+
+	if !updated {
+		return nil, fmt.Errorf("nothing to update")
+	}
+	return input, nil
+}
+
+// checkAddGSI checks the options used with --add-gsi.
+// DynamoDB rejects the update which creates the index with the other changes, such as deleting the index.
+func checkAddGSI(option UpdateTableOption) error {
+	if len(option.AddGSI) == 0 {
+		return nil
+	}
+	switch {
+	case len(option.DeleteGSI) != 0:
+		return fmt.Errorf("use either --add-gsi or --delete-gsi option, the index can be created or deleted one at a time")
+	case len(option.BillingMode) != 0 || option.ReadCapacity != 0 || option.WriteCapacity != 0 ||
+		len(option.GSICapacity) != 0:
+		return fmt.Errorf("--add-gsi can not be used with the billing mode or the capacity, change them separately")
+	case len(option.Stream) != 0:
+		return fmt.Errorf("--add-gsi can not be used with --stream, change the stream separately")
+	case len(option.TableClass) != 0:
+		return fmt.Errorf("--add-gsi can not be used with --table-class, change the table class separately")
+	}
+	return nil
+}
+
+// UpdateTable changes the settings of the table, and waits until the table and the indexes are ACTIVE.
+func (i *Instance) UpdateTable(ctx context.Context, w io.Writer, tableName string, option UpdateTableOption) error {
+	if err := checkAddGSI(option); err != nil {
+		return err
+	}
+	cli := i.createInstance(w)
+	ctx = context.WithValue(ctx, newClientKey, cli)
+
+	t, err := describeTable(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	input, err := updateTableInput(t, option)
+	if err != nil {
+		return err
+	}
+	if _, err := cli.UpdateTable(ctx, input); err != nil {
+		return err
+	}
+	if i.DryRun {
+		return nil
+	}
+
+	if !option.NoWait {
+		if err := waitTableActive(ctx, tableName, option.Progress); err != nil {
+			return err
+		}
+	}
+	t, err = describeTable(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	return printJSON(w, t)
+}
@@ -0,0 +1,375 @@
+package edy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/hirano00o/edy/mocks"
+	"github.com/hirano00o/edy/model"
+)
+
+func TestInstance_UpdateTable(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		tableName string
+		option    UpdateTableOption
+		dryRun    bool
+	}
+	activeGSIOutput := func(t *testing.T) *dynamodb.DescribeTableOutput {
+		t.Helper()
+
+		output := describeTableOutputFixture(t, true)
+		output.Table.TableStatus = types.TableStatusActive
+		output.Table.GlobalSecondaryIndexes[0].IndexStatus = types.IndexStatusActive
+		return output
+	}
+	switchingInput := &dynamodb.UpdateTableInput{
+		TableName:   aws.String("TEST"),
+		BillingMode: types.BillingModeProvisioned,
+		ProvisionedThroughput: &types.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(5),
+		},
+		GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{
+			{
+				Update: &types.UpdateGlobalSecondaryIndexAction{
+					IndexName: aws.String("TEST_GSI"),
+					ProvisionedThroughput: &types.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(5),
+						WriteCapacityUnits: aws.Int64(5),
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		args         args
+		mocking      func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI
+		wantW        string
+		wantProgress string
+		wantErr      bool
+	}{
+		{
+			name: "UpdateTable adds GSI and waits",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option: UpdateTableOption{
+					AddGSI: "indexName: TEST_GSI\npartitionKey: {name: TEST_ATTRIBUTE_1, type: S}\n" +
+						"sortKey: {name: TEST_ATTRIBUTE_2, type: N}\nprojection: {type: KEYS_ONLY}\n",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				input := &dynamodb.DescribeTableInput{TableName: aws.String("TEST")}
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, input).
+					Return(activeDescribeTableOutputFixture(t), nil).Once()
+				m.UpdateTableClient.On("UpdateTable", ctx, &dynamodb.UpdateTableInput{
+					TableName: aws.String("TEST"),
+					AttributeDefinitions: []types.AttributeDefinition{
+						{AttributeName: aws.String("TEST_ATTRIBUTE_1"), AttributeType: types.ScalarAttributeTypeS},
+						{AttributeName: aws.String("TEST_ATTRIBUTE_2"), AttributeType: types.ScalarAttributeTypeN},
+					},
+					GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{
+						{
+							Create: &types.CreateGlobalSecondaryIndexAction{
+								IndexName: aws.String("TEST_GSI"),
+								KeySchema: []types.KeySchemaElement{
+									{AttributeName: aws.String("TEST_ATTRIBUTE_1"), KeyType: types.KeyTypeHash},
+									{AttributeName: aws.String("TEST_ATTRIBUTE_2"), KeyType: types.KeyTypeRange},
+								},
+								Projection: &types.Projection{ProjectionType: types.ProjectionTypeKeysOnly},
+							},
+						},
+					},
+				}).Return(&dynamodb.UpdateTableOutput{}, nil)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, input).Return(activeGSIOutput(t), nil)
+
+				return m
+			},
+			wantW: jsonFixture(t, model.Table{
+				Arn:    "TEST_ARN",
+				Name:   "TEST",
+				Status: "ACTIVE",
+				PartitionKey: &model.Key{
+					Name:    "TEST_PARTITION_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				SortKey: &model.Key{
+					Name:    "TEST_SORT_ATTRIBUTE",
+					TypeStr: "S",
+				},
+				GSI: []*model.GlobalSecondaryIndex{
+					{
+						Name: "TEST_GSI",
+						PartitionKey: &model.Key{
+							Name:    "TEST_ATTRIBUTE_1",
+							TypeStr: "S",
+						},
+						SortKey: &model.Key{
+							Name:    "TEST_ATTRIBUTE_2",
+							TypeStr: "N",
+						},
+						Status: "ACTIVE",
+					},
+				},
+				ItemCount: 1,
+			}),
+			wantProgress: "TEST: ACTIVE, TEST_GSI: ACTIVE\n",
+		},
+		{
+			name: "UpdateTable switches to PROVISIONED with GSI in dry-run mode",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option: UpdateTableOption{
+					BillingMode:   "provisioned",
+					ReadCapacity:  5,
+					WriteCapacity: 5,
+				},
+				dryRun: true,
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", mock.Anything, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeGSIOutput(t), nil)
+
+				return m
+			},
+			wantW: jsonFixture(t, &dryRunRequest{
+				Operation: "UpdateTable",
+				TableName: "TEST",
+				Input:     switchingInput,
+			}),
+		},
+		{
+			name: "UpdateTable changes table class in dry-run mode",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option:    UpdateTableOption{TableClass: "standard_infrequent_access"},
+				dryRun:    true,
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", mock.Anything, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+
+				return m
+			},
+			wantW: jsonFixture(t, &dryRunRequest{
+				Operation: "UpdateTable",
+				TableName: "TEST",
+				Input: &dynamodb.UpdateTableInput{
+					TableName:  aws.String("TEST"),
+					TableClass: types.TableClassStandardInfrequentAccess,
+				},
+			}),
+		},
+		{
+			name: "UpdateTable switches to PROVISIONED without capacity",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option:    UpdateTableOption{BillingMode: "PROVISIONED"},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "UpdateTable deletes GSI which does not exist",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option:    UpdateTableOption{DeleteGSI: "NOT_EXIST"},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeGSIOutput(t), nil)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "UpdateTable adds and deletes GSI at the same time",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option: UpdateTableOption{
+					AddGSI:    "indexName: NEW_GSI\npartitionKey: {name: TEST_ATTRIBUTE_1, type: S}\n",
+					DeleteGSI: "TEST_GSI",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "UpdateTable adds GSI with stream",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option: UpdateTableOption{
+					AddGSI: "indexName: NEW_GSI\npartitionKey: {name: TEST_ATTRIBUTE_1, type: S}\n",
+					Stream: "NEW_IMAGE",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "UpdateTable adds GSI with billing mode",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option: UpdateTableOption{
+					AddGSI:      "indexName: NEW_GSI\npartitionKey: {name: TEST_ATTRIBUTE_1, type: S}\n",
+					BillingMode: "PROVISIONED",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "UpdateTable adds GSI with capacity of GSI",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option: UpdateTableOption{
+					AddGSI:      "indexName: NEW_GSI\npartitionKey: {name: TEST_ATTRIBUTE_1, type: S}\n",
+					GSICapacity: []string{"TEST_GSI:5:5"},
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "UpdateTable adds GSI with table class",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+				option: UpdateTableOption{
+					AddGSI:     "indexName: NEW_GSI\npartitionKey: {name: TEST_ATTRIBUTE_1, type: S}\n",
+					TableClass: "STANDARD_INFREQUENT_ACCESS",
+				},
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				m.On("CreateInstance").Return(m)
+
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name: "UpdateTable without any change",
+			args: args{
+				ctx:       context.Background(),
+				tableName: "TEST",
+			},
+			mocking: func(t *testing.T, ctx context.Context) *mocks.MockDynamoDBAPI {
+				t.Helper()
+
+				m := new(mocks.MockDynamoDBAPI)
+				ctx = context.WithValue(ctx, newClientKey, m)
+				m.On("CreateInstance").Return(m)
+				m.DescribeTableAPIClient.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String("TEST"),
+				}).Return(activeDescribeTableOutputFixture(t), nil)
+
+				return m
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mocking(t, tt.args.ctx)
+			i := &Instance{
+				NewClient: m,
+				DryRun:    tt.args.dryRun,
+			}
+			w := &bytes.Buffer{}
+			progress := &bytes.Buffer{}
+			tt.args.option.Progress = progress
+			err := i.UpdateTable(tt.args.ctx, w, tt.args.tableName, tt.args.option)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateTable() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("UpdateTable() gotW = %v, want %v", gotW, tt.wantW)
+			}
+			if gotProgress := progress.String(); gotProgress != tt.wantProgress {
+				t.Errorf("UpdateTable() gotProgress = %v, want %v", gotProgress, tt.wantProgress)
+			}
+			m.UpdateTableClient.AssertExpectations(t)
+		})
+	}
+}
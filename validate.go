@@ -0,0 +1,160 @@
+package edy
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/model"
+)
+
+// attributeValueType returns the type name of av in DynamoDB JSON such as S and N.
+func attributeValueType(av types.AttributeValue) string {
+	for k := range dynamoDBJSONOf(av) {
+		return k
+	}
+	return ""
+}
+
+// validateKeyAttribute checks that the item has the key attribute of the type in the key schema.
+func validateKeyAttribute(key *model.Key, item map[string]types.AttributeValue) error {
+	v, ok := item[key.Name]
+	if !ok {
+		return fmt.Errorf("required key %s", key.Name)
+	}
+	if t := attributeValueType(v); t != key.Type.String() {
+		return fmt.Errorf("key %s must be type %s, but %s", key.Name, key.Type.String(), t)
+	}
+	switch t := v.(type) {
+	case *types.AttributeValueMemberS:
+		if len(t.Value) == 0 {
+			return fmt.Errorf("key %s must not be empty", key.Name)
+		}
+	case *types.AttributeValueMemberN:
+		if _, err := strconv.ParseFloat(t.Value, 64); err != nil {
+			return fmt.Errorf("key %s is invalid number: %s", key.Name, t.Value)
+		}
+	case *types.AttributeValueMemberB:
+		if len(t.Value) == 0 {
+			return fmt.Errorf("key %s must not be empty", key.Name)
+		}
+	}
+	return nil
+}
+
+// validateKey checks the partition key and the sort key of the item.
+func validateKey(table *model.Table, item map[string]types.AttributeValue) error {
+	if err := validateKeyAttribute(table.PartitionKey, item); err != nil {
+		return err
+	}
+	if table.SortKey != nil {
+		return validateKeyAttribute(table.SortKey, item)
+	}
+	return nil
+}
+
+// validateItem checks the keys of the table and the keys of GSI, which can be omitted in the item.
+func validateItem(table *model.Table, item map[string]types.AttributeValue) error {
+	var errs []string
+	if err := validateKey(table, item); err != nil {
+		errs = append(errs, err.Error())
+	}
+	for _, g := range table.GSI {
+		for _, k := range []*model.Key{g.PartitionKey, g.SortKey} {
+			if k == nil {
+				continue
+			}
+			if _, ok := item[k.Name]; !ok {
+				continue
+			}
+			if err := validateKeyAttribute(k, item); err != nil {
+				errs = append(errs, fmt.Sprintf("%v in index %s", err, g.Name))
+			}
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// invalidItem is the item which does not match the key schema of the table.
+type invalidItem struct {
+	// Index is the 1-based index in the input.
+	Index int    `json:"item"`
+	Error string `json:"error"`
+}
+
+// validatedItemReader skips the items which do not match the key schema, and records them.
+type validatedItemReader struct {
+	itemReader
+	table   *model.Table
+	count   int
+	invalid []*invalidItem
+	// buffered is the valid items which have been read by readAll.
+	buffered []map[string]types.AttributeValue
+	drained  bool
+}
+
+func (r *validatedItemReader) validate(item map[string]types.AttributeValue) bool {
+	r.count++
+	if err := validateItem(r.table, item); err != nil {
+		r.invalid = append(r.invalid, &invalidItem{Index: r.count, Error: err.Error()})
+		return false
+	}
+	return true
+}
+
+func (r *validatedItemReader) next() (map[string]types.AttributeValue, error) {
+	if r.drained {
+		if len(r.buffered) == 0 {
+			return nil, io.EOF
+		}
+		item := r.buffered[0]
+		r.buffered = r.buffered[1:]
+		return item, nil
+	}
+	for {
+		item, err := r.itemReader.next()
+		if err != nil {
+			return nil, err
+		}
+		if r.validate(item) {
+			return item, nil
+		}
+	}
+}
+
+// readAll validates the rest of items before the first write, and buffers the valid items.
+func (r *validatedItemReader) readAll() error {
+	for {
+		item, err := r.next()
+		if err == io.EOF {
+			r.drained = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		r.buffered = append(r.buffered, item)
+	}
+}
+
+// validateItems validates head which has already been read from r, and wraps r to validate the rest of items.
+func validateItems(
+	table *model.Table,
+	head []map[string]types.AttributeValue,
+	r itemReader,
+) ([]map[string]types.AttributeValue, *validatedItemReader) {
+	vr := &validatedItemReader{itemReader: r, table: table}
+	valid := make([]map[string]types.AttributeValue, 0, len(head))
+	for i := range head {
+		if vr.validate(head[i]) {
+			valid = append(valid, head[i])
+		}
+	}
+	return valid, vr
+}
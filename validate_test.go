@@ -0,0 +1,81 @@
+package edy
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/model"
+)
+
+func Test_validateItem(t *testing.T) {
+	table := &model.Table{
+		PartitionKey: &model.Key{Name: "ID", Type: model.N{}},
+		SortKey:      &model.Key{Name: "Name", Type: model.S{}},
+		GSI: []*model.GlobalSecondaryIndex{
+			{
+				Name:         "TEST_GSI",
+				PartitionKey: &model.Key{Name: "Email", Type: model.S{}},
+				SortKey:      &model.Key{Name: "Age", Type: model.N{}},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		item    map[string]types.AttributeValue
+		wantErr string
+	}{
+		{
+			name: "Valid item",
+			item: map[string]types.AttributeValue{
+				"ID":    &types.AttributeValueMemberN{Value: "1"},
+				"Name":  &types.AttributeValueMemberS{Value: "Alice"},
+				"Email": &types.AttributeValueMemberS{Value: "alice@example.com"},
+				"Age":   &types.AttributeValueMemberN{Value: "20"},
+			},
+		},
+		{
+			name: "Valid item without GSI keys",
+			item: map[string]types.AttributeValue{
+				"ID":   &types.AttributeValueMemberN{Value: "1"},
+				"Name": &types.AttributeValueMemberS{Value: "Alice"},
+			},
+		},
+		{
+			name: "Sort key is missing",
+			item: map[string]types.AttributeValue{
+				"ID": &types.AttributeValueMemberN{Value: "1"},
+			},
+			wantErr: "required key Name",
+		},
+		{
+			name: "Sort key is empty string",
+			item: map[string]types.AttributeValue{
+				"ID":   &types.AttributeValueMemberN{Value: "1"},
+				"Name": &types.AttributeValueMemberS{Value: ""},
+			},
+			wantErr: "key Name must not be empty",
+		},
+		{
+			name: "GSI key type is different",
+			item: map[string]types.AttributeValue{
+				"ID":   &types.AttributeValueMemberN{Value: "1"},
+				"Name": &types.AttributeValueMemberS{Value: "Alice"},
+				"Age":  &types.AttributeValueMemberS{Value: "20"},
+			},
+			wantErr: "key Age must be type N, but S in index TEST_GSI",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateItem(table, tt.item)
+			if (err != nil) != (len(tt.wantErr) != 0) {
+				t.Errorf("validateItem() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && err.Error() != tt.wantErr {
+				t.Errorf("validateItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
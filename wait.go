@@ -0,0 +1,98 @@
+package edy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/hirano00o/edy/client"
+)
+
+// tableWaitInterval is the interval to check the status of the table while waiting.
+var tableWaitInterval = 2 * time.Second
+
+const tableWaitMax = 10 * time.Minute
+
+// waitTable describes the table until done returns true.
+func waitTable(
+	ctx context.Context,
+	tableName string,
+	done func(*types.TableDescription, error) (bool, error),
+) error {
+	cli := ctx.Value(newClientKey).(client.DynamoDB)
+
+	timeout := time.After(tableWaitMax)
+	for {
+		res, err := cli.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+			TableName: aws.String(tableName),
+		})
+		var t *types.TableDescription
+		if err == nil {
+			t = res.Table
+		}
+		if ok, err := done(t, err); err != nil || ok {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return fmt.Errorf("timeout waiting for %s", tableName)
+		case <-time.After(tableWaitInterval):
+		}
+	}
+}
+
+func tableStatusText(t *types.TableDescription) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s", aws.ToString(t.TableName), t.TableStatus)
+	for _, g := range t.GlobalSecondaryIndexes {
+		fmt.Fprintf(&sb, ", %s: %s", aws.ToString(g.IndexName), g.IndexStatus)
+		if aws.ToBool(g.Backfilling) {
+			sb.WriteString(" (backfilling)")
+		}
+	}
+	return sb.String()
+}
+
+// waitTableActive waits until the table and the indexes are ACTIVE.
+// The status is written to progress whenever it changes.
+func waitTableActive(ctx context.Context, tableName string, progress io.Writer) error {
+	var last string
+	return waitTable(ctx, tableName, func(t *types.TableDescription, err error) (bool, error) {
+		if err != nil {
+			return false, err
+		}
+		if s := tableStatusText(t); s != last {
+			progressf(progress, "%s\n", s)
+			last = s
+		}
+		if t.TableStatus != types.TableStatusActive {
+			return false, nil
+		}
+		for i := range t.GlobalSecondaryIndexes {
+			if t.GlobalSecondaryIndexes[i].IndexStatus != types.IndexStatusActive {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// waitTableDeleted waits until the table does not exist.
+func waitTableDeleted(ctx context.Context, tableName string) error {
+	return waitTable(ctx, tableName, func(_ *types.TableDescription, err error) (bool, error) {
+		var rnf *types.ResourceNotFoundException
+		if errors.As(err, &rnf) {
+			return true, nil
+		}
+		return false, err
+	})
+}